@@ -35,7 +35,9 @@ func ActivateStructFactoryForType(structType reflect.Type) (ServiceFactoryFunc,
 			if err != nil {
 				return ServiceInstance{}, err
 			}
-			elem.Field(i).Set(reflect.ValueOf(service))
+			if service != nil {
+				elem.Field(i).Set(reflect.ValueOf(service))
+			}
 		}
 		instance := result.Interface()
 		return ServiceInstance{
@@ -123,7 +125,11 @@ func ActivateFuncFactoryForType(function any) (ServiceFactoryFunc, error) {
 			if err != nil {
 				return ServiceInstance{}, err
 			}
-			args[i] = reflect.ValueOf(service)
+			if service == nil {
+				args[i] = reflect.Zero(requirements[i])
+			} else {
+				args[i] = reflect.ValueOf(service)
+			}
 		}
 
 		funcResult := valueOfFunc.Call(args)
@@ -0,0 +1,341 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var (
+	ErrBackgroundServicePanicked = errors.New("background service panicked")
+	ErrWorkerAlreadyStarted      = errors.New("background worker already started")
+)
+
+// BackgroundService is a long running service, run by a background worker.
+// Run must return once ctx is done.
+type BackgroundService interface {
+	Run(ctx context.Context) error
+}
+
+// RestartPolicy tells when a background worker runs its service again.
+type RestartPolicy int
+
+const (
+	// RestartNever runs the service once.
+	RestartNever RestartPolicy = iota
+	// RestartOnFailure runs the service again when it returns an error or
+	// panics, waiting longer after each consecutive failure.
+	RestartOnFailure
+	// RestartAlways runs the service again whenever it returns.
+	RestartAlways
+)
+
+// String implements fmt.Stringer
+func (policy RestartPolicy) String() string {
+	switch policy {
+	case RestartNever:
+		return "Never"
+	case RestartOnFailure:
+		return "OnFailure"
+	case RestartAlways:
+		return "Always"
+	default:
+		return fmt.Sprintf("RestartPolicy(%d)", int(policy))
+	}
+}
+
+// DefaultWorkerInitialBackoff and DefaultWorkerMaxBackoff are the delays used
+// before restarting a service, unless set in WorkerOptions.
+const (
+	DefaultWorkerInitialBackoff = 100 * time.Millisecond
+	DefaultWorkerMaxBackoff     = 30 * time.Second
+)
+
+// WorkerOptions configures a background worker.
+type WorkerOptions struct {
+	// Restart tells when the service is run again.
+	Restart RestartPolicy
+	// InitialBackoff is the delay before restarting the service. It doubles
+	// after each consecutive failure.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay before restarting the service.
+	MaxBackoff time.Duration
+}
+
+// WorkerState is the state of a background worker.
+type WorkerState int
+
+const (
+	// WorkerPending is the state of a worker not started yet.
+	WorkerPending WorkerState = iota
+	// WorkerRunning is the state of a worker running its service.
+	WorkerRunning
+	// WorkerRestarting is the state of a worker waiting to run its service again.
+	WorkerRestarting
+	// WorkerCompleted is the state of a worker whose service returned without
+	// error, and is not run again.
+	WorkerCompleted
+	// WorkerFailed is the state of a worker whose service failed, and is not
+	// run again.
+	WorkerFailed
+	// WorkerStopped is the state of a worker stopped by its host or container.
+	WorkerStopped
+)
+
+// String implements fmt.Stringer
+func (state WorkerState) String() string {
+	switch state {
+	case WorkerPending:
+		return "Pending"
+	case WorkerRunning:
+		return "Running"
+	case WorkerRestarting:
+		return "Restarting"
+	case WorkerCompleted:
+		return "Completed"
+	case WorkerFailed:
+		return "Failed"
+	case WorkerStopped:
+		return "Stopped"
+	default:
+		return fmt.Sprintf("WorkerState(%d)", int(state))
+	}
+}
+
+// WorkerStatus reports the activity of a background worker.
+type WorkerStatus struct {
+	// Name is the type of the background service.
+	Name  string
+	State WorkerState
+	// Runs counts the times the service was run.
+	Runs int
+	// Failures counts the runs that returned an error or panicked.
+	Failures int
+	// LastError is the error of the last failed run.
+	LastError error
+	// LastStarted is the time the service was last run.
+	LastStarted time.Time
+}
+
+// BackgroundWorker supervises the runs of a background service. Every
+// registered worker can be resolved as []BackgroundWorker to report statuses.
+type BackgroundWorker interface {
+	HostedService
+	Disposable
+	Status() WorkerStatus
+}
+
+// AddBackgroundService registers a hosted background worker for the service
+// of type T, which must be registered separately, preferably as scoped or
+// transient. Each run of the service is resolved in a new scope, disposed
+// once the run returns. A panic in Run is recovered as a failed run.
+// Stopping the host or disposing the container cancels the run, and waits
+// for it to return. The container disposes the other services once every
+// worker stopped.
+func AddBackgroundService[T BackgroundService](services ServiceCollection, options WorkerOptions) ServiceCollection {
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = DefaultWorkerInitialBackoff
+	}
+	if options.MaxBackoff < options.InitialBackoff {
+		options.MaxBackoff = max(DefaultWorkerMaxBackoff, options.InitialBackoff)
+	}
+
+	workerType := typeOf[*backgroundWorker[T]]()
+	services.Add(NewSingletonServiceFactory[*backgroundWorker[T]](NewFactoryWith(
+		"BackgroundWorker["+typeOf[T]().String()+"]",
+		[]reflect.Type{typeOfServiceContainer},
+		func(provider ServiceProvider) (any, error) {
+			container, err := GetService[ServiceContainer](provider)
+			if err != nil {
+				return nil, err
+			}
+			return newBackgroundWorker[T](container, options), nil
+		})))
+	services.Add(NewSingletonServiceFactory[BackgroundWorker](NewFactoryWith(
+		"BackgroundWorker["+typeOf[T]().String()+"]",
+		[]reflect.Type{workerType},
+		func(provider ServiceProvider) (any, error) {
+			return GetService[*backgroundWorker[T]](provider)
+		})))
+	AddHostedServiceForType(services, workerType)
+
+	return services.AddValidator(func(provider ServiceProvider) error {
+		if provider.GetServiceInfo(typeOf[T]()).IsNotFound() {
+			return newServiceResolutionError(nil, typeOf[T](), ErrServiceNotFound)
+		}
+		return nil
+	})
+}
+
+// backgroundWorker is the BackgroundWorker implementation
+type backgroundWorker[T BackgroundService] struct {
+	mutex     sync.Mutex
+	container ServiceContainer
+	options   WorkerOptions
+	status    WorkerStatus
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+var _ BackgroundWorker = (*backgroundWorker[BackgroundService])(nil)
+var _ stopper = (*backgroundWorker[BackgroundService])(nil)
+
+func newBackgroundWorker[T BackgroundService](container ServiceContainer, options WorkerOptions) *backgroundWorker[T] {
+	return &backgroundWorker[T]{
+		container: container,
+		options:   options,
+		status:    WorkerStatus{Name: typeOf[T]().String()},
+	}
+}
+
+// Start implements HostedService. The worker runs until stopped, so ctx only
+// bounds the start itself.
+func (worker *backgroundWorker[T]) Start(ctx context.Context) error {
+	worker.mutex.Lock()
+	defer worker.mutex.Unlock()
+
+	if worker.done != nil {
+		return ErrWorkerAlreadyStarted
+	}
+	runCtx, cancel := context.WithCancel(context.Background())
+	worker.cancel = cancel
+	worker.done = make(chan struct{})
+
+	go worker.supervise(runCtx, worker.done)
+	return nil
+}
+
+// Stop implements HostedService. It cancels the running service, and waits
+// for it to return or for ctx to be done.
+func (worker *backgroundWorker[T]) Stop(ctx context.Context) error {
+	done := worker.stop()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Dispose implements Disposable. It cancels the running service, and waits
+// for it to return.
+func (worker *backgroundWorker[T]) Dispose() {
+	<-worker.stop()
+}
+
+// stopBeforeDispose implements stopper, so that the services used by a
+// running service are disposed once it returned.
+func (worker *backgroundWorker[T]) stopBeforeDispose() {
+	<-worker.stop()
+}
+
+// stop cancels the supervision, and returns a channel closed once it ends.
+func (worker *backgroundWorker[T]) stop() <-chan struct{} {
+	worker.mutex.Lock()
+	defer worker.mutex.Unlock()
+
+	if worker.done == nil {
+		// Never started: make sure it never will be
+		worker.cancel = func() {}
+		worker.done = make(chan struct{})
+		close(worker.done)
+		worker.status.State = WorkerStopped
+		return worker.done
+	}
+	worker.cancel()
+	return worker.done
+}
+
+// Status implements BackgroundWorker
+func (worker *backgroundWorker[T]) Status() WorkerStatus {
+	worker.mutex.Lock()
+	defer worker.mutex.Unlock()
+	return worker.status
+}
+
+func (worker *backgroundWorker[T]) supervise(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	backoff := worker.options.InitialBackoff
+	for {
+		worker.update(func(status *WorkerStatus) {
+			status.State = WorkerRunning
+			status.Runs++
+			status.LastStarted = time.Now()
+		})
+
+		err := worker.run(ctx)
+
+		if ctx.Err() != nil {
+			worker.update(func(status *WorkerStatus) { status.State = WorkerStopped })
+			return
+		}
+
+		restart := worker.options.Restart == RestartAlways ||
+			(worker.options.Restart == RestartOnFailure && err != nil)
+		delay := worker.options.InitialBackoff
+		if err != nil {
+			delay = backoff
+			backoff = min(backoff*2, worker.options.MaxBackoff)
+		} else {
+			backoff = worker.options.InitialBackoff
+		}
+
+		worker.update(func(status *WorkerStatus) {
+			if err != nil {
+				status.Failures++
+				status.LastError = err
+			}
+			switch {
+			case restart:
+				status.State = WorkerRestarting
+			case err != nil:
+				status.State = WorkerFailed
+			default:
+				status.State = WorkerCompleted
+			}
+		})
+		if !restart {
+			return
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			worker.update(func(status *WorkerStatus) { status.State = WorkerStopped })
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// run resolves the service in a new scope, and runs it once.
+func (worker *backgroundWorker[T]) run(ctx context.Context) (err error) {
+	scope, err := worker.container.CreateScope()
+	if err != nil {
+		return err
+	}
+	defer scope.Dispose()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%w: %v", ErrBackgroundServicePanicked, recovered)
+		}
+	}()
+
+	service, err := GetService[T](scope.Provider())
+	if err != nil {
+		return err
+	}
+	return service.Run(ctx)
+}
+
+func (worker *backgroundWorker[T]) update(change func(status *WorkerStatus)) {
+	worker.mutex.Lock()
+	defer worker.mutex.Unlock()
+	change(&worker.status)
+}
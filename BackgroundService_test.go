@@ -0,0 +1,285 @@
+package di
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testWorkerRecorder counts the runs and disposals of testBackgroundService.
+type testWorkerRecorder struct {
+	mutex    sync.Mutex
+	runs     int
+	disposed int
+	exited   atomic.Bool
+	// behavior returns the outcome of the given run, counted from 1.
+	behavior func(ctx context.Context, run int) error
+}
+
+type testBackgroundService struct {
+	recorder *testWorkerRecorder
+}
+
+func (service *testBackgroundService) Run(ctx context.Context) error {
+	service.recorder.mutex.Lock()
+	service.recorder.runs++
+	run := service.recorder.runs
+	service.recorder.mutex.Unlock()
+	return service.recorder.behavior(ctx, run)
+}
+
+func (service *testBackgroundService) Dispose() {
+	service.recorder.mutex.Lock()
+	defer service.recorder.mutex.Unlock()
+	service.recorder.disposed++
+}
+
+func newTestWorkerServices(
+	options WorkerOptions,
+	behavior func(ctx context.Context, run int) error,
+) (ServiceCollection, *testWorkerRecorder) {
+	recorder := &testWorkerRecorder{behavior: behavior}
+	services := NewServiceCollection()
+	services.Add(NewScopedFactory[*testBackgroundService](func(ServiceProvider) (any, error) {
+		return &testBackgroundService{recorder: recorder}, nil
+	}))
+	return AddBackgroundService[*testBackgroundService](services, options), recorder
+}
+
+func startTestWorker(t *testing.T, services ServiceCollection) (ServiceContainer, BackgroundWorker) {
+	container, err := services.Build()
+	assert.NoError(t, err)
+	workers, err := GetService[[]BackgroundWorker](container.Provider())
+	assert.NoError(t, err)
+	assert.Len(t, workers, 1)
+	assert.NoError(t, workers[0].Start(context.Background()))
+	return container, workers[0]
+}
+
+func waitWorkerState(t *testing.T, worker BackgroundWorker, state WorkerState) WorkerStatus {
+	assert.Eventually(t, func() bool {
+		return worker.Status().State == state
+	}, time.Second, time.Millisecond)
+	return worker.Status()
+}
+
+func TestBackgroundService_RunsOnceInScope(t *testing.T) {
+	services, recorder := newTestWorkerServices(WorkerOptions{}, func(context.Context, int) error {
+		return nil
+	})
+	container, worker := startTestWorker(t, services)
+	defer container.Dispose()
+
+	status := waitWorkerState(t, worker, WorkerCompleted)
+	assert.Equal(t, "*di.testBackgroundService", status.Name)
+	assert.Equal(t, 1, status.Runs)
+	assert.Equal(t, 0, status.Failures)
+	assert.Nil(t, status.LastError)
+	assert.False(t, status.LastStarted.IsZero())
+	assert.Equal(t, 1, recorder.disposed)
+}
+
+func TestBackgroundService_RestartAlways(t *testing.T) {
+	services, recorder := newTestWorkerServices(
+		WorkerOptions{Restart: RestartAlways, InitialBackoff: time.Millisecond},
+		func(ctx context.Context, run int) error {
+			if run < 3 {
+				return nil
+			}
+			<-ctx.Done()
+			return nil
+		})
+	container, worker := startTestWorker(t, services)
+
+	assert.Eventually(t, func() bool {
+		return worker.Status().Runs == 3
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, WorkerRunning, worker.Status().State)
+
+	container.Dispose()
+	assert.Equal(t, WorkerStopped, worker.Status().State)
+	// A new scope per run, each disposed after the run
+	assert.Equal(t, 3, recorder.disposed)
+}
+
+func TestBackgroundService_RestartOnFailureWithBackoff(t *testing.T) {
+	var started []time.Time
+	services, _ := newTestWorkerServices(
+		WorkerOptions{Restart: RestartOnFailure, InitialBackoff: 10 * time.Millisecond, MaxBackoff: time.Second},
+		func(ctx context.Context, run int) error {
+			started = append(started, time.Now())
+			if run < 3 {
+				return errTestFactory
+			}
+			return nil
+		})
+	container, worker := startTestWorker(t, services)
+	defer container.Dispose()
+
+	status := waitWorkerState(t, worker, WorkerCompleted)
+	assert.Equal(t, 3, status.Runs)
+	assert.Equal(t, 2, status.Failures)
+	assert.ErrorIs(t, status.LastError, errTestFactory)
+	// The backoff doubles after consecutive failures
+	assert.GreaterOrEqual(t, started[1].Sub(started[0]), 10*time.Millisecond)
+	assert.GreaterOrEqual(t, started[2].Sub(started[1]), 20*time.Millisecond)
+}
+
+func TestBackgroundService_PanicRecovered(t *testing.T) {
+	services, recorder := newTestWorkerServices(WorkerOptions{}, func(context.Context, int) error {
+		panic("boom")
+	})
+	container, worker := startTestWorker(t, services)
+	defer container.Dispose()
+
+	status := waitWorkerState(t, worker, WorkerFailed)
+	assert.Equal(t, 1, status.Failures)
+	assert.ErrorIs(t, status.LastError, ErrBackgroundServicePanicked)
+	assert.Contains(t, status.LastError.Error(), "boom")
+	assert.Equal(t, 1, recorder.disposed)
+}
+
+func TestBackgroundService_DisposeWaitsForRun(t *testing.T) {
+	var recorder *testWorkerRecorder
+	services, recorder := newTestWorkerServices(WorkerOptions{}, func(ctx context.Context, _ int) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		recorder.exited.Store(true)
+		return ctx.Err()
+	})
+	container, worker := startTestWorker(t, services)
+	waitWorkerState(t, worker, WorkerRunning)
+
+	container.Dispose()
+	assert.True(t, recorder.exited.Load())
+	status := worker.Status()
+	assert.Equal(t, WorkerStopped, status.State)
+	assert.Equal(t, 0, status.Failures)
+}
+
+type testWorkerResource struct {
+	disposed atomic.Bool
+	// usedDisposed tells whether the resource was disposed when last used.
+	usedDisposed atomic.Bool
+}
+
+func (resource *testWorkerResource) Dispose() {
+	resource.disposed.Store(true)
+}
+
+type testResourceWorker struct {
+	Resource *testWorkerResource
+}
+
+func (worker *testResourceWorker) Run(ctx context.Context) error {
+	<-ctx.Done()
+	time.Sleep(10 * time.Millisecond)
+	worker.Resource.usedDisposed.Store(worker.Resource.disposed.Load())
+	return ctx.Err()
+}
+
+func TestBackgroundService_DisposeStopsBeforeSingletons(t *testing.T) {
+	resource := &testWorkerResource{}
+	services := NewServiceCollection().Add(NewSingletonFactory[*testWorkerResource](func(ServiceProvider) (any, error) {
+		return resource, nil
+	}))
+	service, _ := NewScopedStructPtr[testResourceWorker]()
+	services = AddBackgroundService[*testResourceWorker](services.Add(service), WorkerOptions{})
+	container, worker := startTestWorker(t, services)
+	waitWorkerState(t, worker, WorkerRunning)
+	assert.Eventually(t, func() bool {
+		return container.Provider().GetServiceInfo(typeOf[*testWorkerResource]()).IsInstantiated
+	}, time.Second, time.Millisecond)
+
+	container.Dispose()
+	assert.True(t, resource.disposed.Load())
+	assert.False(t, resource.usedDisposed.Load())
+	assert.Equal(t, WorkerStopped, worker.Status().State)
+}
+
+func TestBackgroundService_StopTimeout(t *testing.T) {
+	release := make(chan struct{})
+	services, _ := newTestWorkerServices(WorkerOptions{}, func(context.Context, int) error {
+		<-release
+		return nil
+	})
+	container, worker := startTestWorker(t, services)
+	waitWorkerState(t, worker, WorkerRunning)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, worker.Stop(ctx), context.DeadlineExceeded)
+
+	close(release)
+	container.Dispose()
+	assert.Equal(t, WorkerStopped, worker.Status().State)
+}
+
+func TestBackgroundService_StartedTwice(t *testing.T) {
+	services, _ := newTestWorkerServices(WorkerOptions{}, func(context.Context, int) error {
+		return nil
+	})
+	container, worker := startTestWorker(t, services)
+	defer container.Dispose()
+
+	assert.ErrorIs(t, worker.Start(context.Background()), ErrWorkerAlreadyStarted)
+}
+
+func TestBackgroundService_NeverStarted(t *testing.T) {
+	services, recorder := newTestWorkerServices(WorkerOptions{}, func(context.Context, int) error {
+		return nil
+	})
+	container, err := services.Build()
+	assert.NoError(t, err)
+	worker, err := GetService[BackgroundWorker](container.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, WorkerPending, worker.Status().State)
+
+	container.Dispose()
+	assert.Equal(t, WorkerStopped, worker.Status().State)
+	assert.ErrorIs(t, worker.Start(context.Background()), ErrWorkerAlreadyStarted)
+	assert.Equal(t, 0, recorder.runs)
+}
+
+func TestBackgroundService_Host(t *testing.T) {
+	services, recorder := newTestWorkerServices(WorkerOptions{}, func(ctx context.Context, _ int) error {
+		<-ctx.Done()
+		return nil
+	})
+	host, err := NewHost(services, HostOptions{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, host.Start(context.Background()))
+	worker, err := GetService[BackgroundWorker](host.Provider())
+	assert.NoError(t, err)
+	waitWorkerState(t, worker, WorkerRunning)
+
+	assert.NoError(t, host.Stop(context.Background()))
+	assert.Equal(t, WorkerStopped, worker.Status().State)
+	assert.Equal(t, 1, recorder.disposed)
+}
+
+func TestAddBackgroundService_NotRegistered(t *testing.T) {
+	services := AddBackgroundService[*testBackgroundService](NewServiceCollection(), WorkerOptions{})
+
+	container, err := services.Build()
+	assert.Nil(t, container)
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+}
+
+func TestRestartPolicy_String(t *testing.T) {
+	assert.Equal(t, "Never", RestartNever.String())
+	assert.Equal(t, "OnFailure", RestartOnFailure.String())
+	assert.Equal(t, "Always", RestartAlways.String())
+	assert.Equal(t, "RestartPolicy(7)", RestartPolicy(7).String())
+}
+
+func TestWorkerState_String(t *testing.T) {
+	assert.Equal(t, "Running", WorkerRunning.String())
+	assert.Equal(t, "Stopped", WorkerStopped.String())
+	assert.Equal(t, "WorkerState(9)", WorkerState(9).String())
+}
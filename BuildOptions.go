@@ -0,0 +1,57 @@
+package di
+
+import "errors"
+
+var ErrNilServiceInstance = errors.New("service factory returned a nil instance")
+
+// DuplicatePolicy tells how Build handles service types registered more than once.
+type DuplicatePolicy int
+
+const (
+	// DuplicatesAllowed resolves the last registration of a service type,
+	// and every registration when resolved as a slice.
+	DuplicatesAllowed DuplicatePolicy = iota
+	// DuplicatesStrict makes Build fail when a service type registered more
+	// than once is required as a single service, since only its last
+	// registration would be used. Service types only required as slices,
+	// like hosted services, can still be registered more than once.
+	DuplicatesStrict
+)
+
+// NilInstancePolicy tells how containers handle factories returning nil.
+type NilInstancePolicy int
+
+const (
+	// NilInstancesAllowed resolves nil instances like any other.
+	NilInstancesAllowed NilInstancePolicy = iota
+	// NilInstancesRejected fails the resolution of services whose factory
+	// returned nil, with ErrNilServiceInstance.
+	NilInstancesRejected
+)
+
+// BuildOptions configures how a service collection is built into a container.
+// The zero value is what Build uses.
+type BuildOptions struct {
+	// SkipValidation skips the validation of the descriptors' requirements.
+	// Validators added with AddValidator still run.
+	SkipValidation bool
+	// SkipScopeValidation allows singletons to require scoped services in
+	// the validation. Resolving them from the root container still fails.
+	SkipScopeValidation bool
+	// Duplicates tells how service types registered more than once are handled.
+	Duplicates DuplicatePolicy
+	// NilInstances tells how factories returning nil are handled.
+	NilInstances NilInstancePolicy
+	// WarmUp instantiates singletons once validated, like AddWarmUp, when not nil.
+	WarmUp *WarmUpOptions
+	// Hooks are invoked by the built containers, after the ones added with AddHooks.
+	Hooks []ContainerHooks
+}
+
+func (options BuildOptions) validationRules() validationRules {
+	return validationRules{
+		skip:             options.SkipValidation,
+		skipScopes:       options.SkipScopeValidation,
+		strictDuplicates: options.Duplicates == DuplicatesStrict,
+	}
+}
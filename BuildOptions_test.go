@@ -0,0 +1,168 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestScopedDependencyServices(t *testing.T) ServiceCollection {
+	dependency, err := NewScopedStruct[testServiceInterface, testServiceStruct]()
+	assert.NoError(t, err)
+	dependent, err := NewSingletonStructPtr[testStructWithDependency]()
+	assert.NoError(t, err)
+	return NewServiceCollection().AddRange(dependency, dependent)
+}
+
+func TestBuildWithOptions_ScopeValidation(t *testing.T) {
+	container, err := newTestScopedDependencyServices(t).BuildWithOptions(BuildOptions{})
+	assert.Nil(t, container)
+	assert.ErrorContains(t, err, "=(invalid)=>")
+
+	container, err = newTestScopedDependencyServices(t).BuildWithOptions(BuildOptions{SkipScopeValidation: true})
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	_, err = GetService[*testStructWithDependency](container.Provider())
+	assert.ErrorIs(t, err, ErrScopedServiceFromRoot)
+}
+
+func TestBuildWithOptions_SkipValidation(t *testing.T) {
+	dependent, err := NewSingletonStructPtr[testStructWithDependency]()
+	assert.NoError(t, err)
+
+	container, err := NewServiceCollection().Add(dependent).BuildWithOptions(BuildOptions{})
+	assert.Nil(t, container)
+	assert.ErrorContains(t, err, "=(not found)=>")
+
+	validated := false
+	container, err = NewServiceCollection().
+		Add(dependent).
+		AddValidator(func(ServiceProvider) error {
+			validated = true
+			return nil
+		}).
+		BuildWithOptions(BuildOptions{SkipValidation: true})
+	assert.NoError(t, err)
+	defer container.Dispose()
+	assert.True(t, validated)
+
+	_, err = GetService[*testStructWithDependency](container.Provider())
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+}
+
+func TestBuildWithOptions_StrictDuplicates(t *testing.T) {
+	newServices := func() ServiceCollection {
+		first, err := NewSingletonStruct[testServiceInterface, testServiceStruct]()
+		assert.NoError(t, err)
+		second, err := NewSingletonStruct[testServiceInterface, testServiceStruct]()
+		assert.NoError(t, err)
+		return NewServiceCollection().AddRange(first, second)
+	}
+
+	// Duplicates only resolved as slices are allowed
+	container, err := newServices().BuildWithOptions(BuildOptions{Duplicates: DuplicatesStrict})
+	assert.NoError(t, err)
+	container.Dispose()
+
+	dependent, err := NewSingletonStructPtr[testStructWithDependency]()
+	assert.NoError(t, err)
+
+	container, err = newServices().Add(dependent).BuildWithOptions(BuildOptions{})
+	assert.NoError(t, err)
+	container.Dispose()
+
+	container, err = newServices().Add(dependent).BuildWithOptions(BuildOptions{Duplicates: DuplicatesStrict})
+	assert.Nil(t, container)
+	assert.ErrorContains(t, err, "=(ambiguous)=> di.testServiceInterface fails: registered 2 times")
+}
+
+func TestBuildWithOptions_NilInstances(t *testing.T) {
+	newServices := func() ServiceCollection {
+		return NewServiceCollection().Add(NewScopedFactory[*testStructWithFields](func(ServiceProvider) (any, error) {
+			return (*testStructWithFields)(nil), nil
+		}))
+	}
+
+	container, err := newServices().BuildWithOptions(BuildOptions{})
+	assert.NoError(t, err)
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+	instance, err := GetService[*testStructWithFields](scope.Provider())
+	assert.NoError(t, err)
+	assert.Nil(t, instance)
+	container.Dispose()
+
+	container, err = newServices().BuildWithOptions(BuildOptions{NilInstances: NilInstancesRejected})
+	assert.NoError(t, err)
+	defer container.Dispose()
+	scope, err = container.CreateScope()
+	assert.NoError(t, err)
+	_, err = GetService[*testStructWithFields](scope.Provider())
+	assert.ErrorIs(t, err, ErrNilServiceInstance)
+}
+
+type testNilUser struct{}
+
+type testNilUserHandler struct {
+	User *testNilUser
+}
+
+func TestBuildWithOptions_NilInstancesInjected(t *testing.T) {
+	handler, _ := NewSingletonStructPtr[testNilUserHandler]()
+	container, err := NewServiceCollection().Add(handler).Add(NewSingletonFactory[*testNilUser](
+		func(ServiceProvider) (any, error) {
+			return nil, nil
+		})).Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	instance, err := GetService[*testNilUserHandler](container.Provider())
+	assert.NoError(t, err)
+	assert.Nil(t, instance.User)
+}
+
+func TestBuildWithOptions_WarmUp(t *testing.T) {
+	var invoked []string
+	services := NewServiceCollection()
+	addTestWarmServices(services, &invoked, "")
+
+	container, err := services.BuildWithOptions(BuildOptions{WarmUp: &WarmUpOptions{All: true}})
+	assert.NoError(t, err)
+	defer container.Dispose()
+	assert.Equal(t, []string{"C", "B", "A"}, invoked)
+
+	invoked = nil
+	services = NewServiceCollection()
+	addTestWarmServices(services, &invoked, "C")
+	container, err = services.BuildWithOptions(BuildOptions{WarmUp: &WarmUpOptions{All: true}})
+	assert.Nil(t, container)
+	assert.ErrorIs(t, err, errTestFactory)
+}
+
+func TestBuildWithOptions_Hooks(t *testing.T) {
+	var events []string
+	services := NewServiceCollection().AddHooks(ContainerHooks{
+		OnBuild: func(BuildEvent) { events = append(events, "collection build") },
+	})
+
+	container, err := services.BuildWithOptions(BuildOptions{
+		Hooks: []ContainerHooks{{
+			OnBuild:        func(BuildEvent) { events = append(events, "options build") },
+			OnScopeCreated: func(ScopeEvent) { events = append(events, "options scope") },
+		}},
+	})
+	assert.NoError(t, err)
+	defer container.Dispose()
+	_, err = container.CreateScope()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"options scope", "collection build", "options build", "options scope"}, events)
+
+	// The options hooks are not kept by the collection
+	events = nil
+	container, err = services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+	assert.Equal(t, []string{"collection build"}, events)
+}
@@ -0,0 +1,70 @@
+package di
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// CallSite is the location of the code which created a service descriptor.
+type CallSite struct {
+	File string
+	Line int
+}
+
+// IsKnown tells whether the call site was recorded.
+func (site CallSite) IsKnown() bool {
+	return site.File != ""
+}
+
+// String returns the file, with its directory only, and line of the call site.
+func (site CallSite) String() string {
+	if !site.IsKnown() {
+		return "<unknown>"
+	}
+	file := filepath.Join(filepath.Base(filepath.Dir(site.File)), filepath.Base(site.File))
+	return fmt.Sprintf("%s:%d", filepath.ToSlash(file), site.Line)
+}
+
+// skipCallSites disables the recording of call sites when set.
+var skipCallSites atomic.Bool
+
+// RecordCallSites enables or disables the recording of call sites by
+// descriptor constructors, which is enabled by default. Recording walks the
+// stack, so it can be disabled when registering many descriptors matters.
+func RecordCallSites(enabled bool) {
+	skipCallSites.Store(!enabled)
+}
+
+// packageDir is the directory of the files of this package.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// captureCallSite returns the first caller outside of this package, so that
+// helpers registering descriptors report the code calling them.
+func captureCallSite() CallSite {
+	if skipCallSites.Load() {
+		return CallSite{}
+	}
+
+	pcs := make([]uintptr, 32)
+	count := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:count])
+	for {
+		frame, more := frames.Next()
+		if !isPackageFile(frame.File) {
+			return CallSite{File: frame.File, Line: frame.Line}
+		}
+		if !more {
+			return CallSite{}
+		}
+	}
+}
+
+func isPackageFile(file string) bool {
+	return filepath.Dir(file) == packageDir && !strings.HasSuffix(file, "_test.go")
+}
@@ -0,0 +1,63 @@
+package di
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withoutCallSites disables call sites for the duration of a test, for
+// assertions on descriptor strings.
+func withoutCallSites(t *testing.T) {
+	RecordCallSites(false)
+	t.Cleanup(func() { RecordCallSites(true) })
+}
+
+func TestCallSite_Descriptor(t *testing.T) {
+	_, file, line, _ := runtime.Caller(0)
+	descriptor := NewSingletonFactory[*testStructWithFields](func(ServiceProvider) (any, error) {
+		return &testStructWithFields{}, nil
+	})
+
+	site := descriptor.CallSite()
+	assert.True(t, site.IsKnown())
+	assert.Equal(t, file, site.File)
+	assert.Equal(t, line+1, site.Line)
+	assert.Contains(t, descriptor.String(), "[Singleton] *di.testStructWithFields (")
+	assert.Contains(t, descriptor.String(), "/CallSite_test.go:")
+}
+
+func TestCallSite_ThroughHelpers(t *testing.T) {
+	services := NewServiceCollection()
+	_, file, line, _ := runtime.Caller(0)
+	AddHostedService[*testHostedDatabase](services)
+
+	site := services.ListDescriptors()[0].CallSite()
+	assert.Equal(t, file, site.File)
+	assert.Equal(t, line+1, site.Line)
+}
+
+func TestCallSite_InValidationErrors(t *testing.T) {
+	descriptor, err := NewSingletonStructPtr[testStructWithDependency]()
+	assert.NoError(t, err)
+
+	_, err = NewServiceCollection().Add(descriptor).Build()
+	assert.ErrorContains(t, err, descriptor.CallSite().String()+") =(not found)=>")
+}
+
+func TestRecordCallSites(t *testing.T) {
+	withoutCallSites(t)
+	descriptor := NewSingletonFactory[*testStructWithFields](func(ServiceProvider) (any, error) {
+		return &testStructWithFields{}, nil
+	})
+
+	assert.False(t, descriptor.CallSite().IsKnown())
+	assert.Equal(t, "<unknown>", descriptor.CallSite().String())
+	assert.Equal(t, "[Singleton] *di.testStructWithFields", descriptor.String())
+}
+
+func TestCallSite_String(t *testing.T) {
+	site := CallSite{File: "/home/app/internal/repo/repo.go", Line: 42}
+	assert.Equal(t, "repo/repo.go:42", site.String())
+}
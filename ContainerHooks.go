@@ -0,0 +1,119 @@
+package di
+
+import (
+	"reflect"
+	"time"
+)
+
+// ContainerHooks is a set of optional callbacks invoked by a service container
+// while it builds, resolves and disposes services.
+// Every field may be left nil, and a nil hook costs nothing at runtime.
+type ContainerHooks struct {
+	// OnBuild is called once a service collection has been validated.
+	OnBuild func(event BuildEvent)
+	// OnResolved is called every time a descriptor is resolved, either from
+	// the container cache or by invoking its factory.
+	OnResolved func(event ResolvedEvent)
+	// OnInstantiated is called after a service factory has been invoked.
+	OnInstantiated func(event InstantiatedEvent)
+	// OnResolveFailed is called when a top-level service request fails.
+	OnResolveFailed func(event ResolveFailedEvent)
+	// OnScopeCreated is called when a container or scope is created.
+	OnScopeCreated func(event ScopeEvent)
+	// OnScopeDisposed is called when a container or scope is disposed.
+	OnScopeDisposed func(event ScopeEvent)
+	// OnDisposeFailed is called when disposing a service instance panics.
+	OnDisposeFailed func(event DisposeFailedEvent)
+}
+
+// BuildEvent describes the validation of a service collection.
+type BuildEvent struct {
+	Descriptors int
+	Duration    time.Duration
+	Err         error
+}
+
+// ResolvedEvent describes the resolution of a single descriptor.
+type ResolvedEvent struct {
+	Descriptor ServiceDescriptor
+	ScopeID    uint64
+	Cached     bool
+}
+
+// InstantiatedEvent describes the invocation of a service factory.
+type InstantiatedEvent struct {
+	Descriptor ServiceDescriptor
+	ScopeID    uint64
+	Duration   time.Duration
+	Err        error
+}
+
+// ResolveFailedEvent describes a failed top-level service request.
+type ResolveFailedEvent struct {
+	ServiceType reflect.Type
+	ScopeID     uint64
+	Err         error
+}
+
+// ScopeEvent describes the creation or disposal of a container or scope.
+// ParentID is zero for root containers.
+type ScopeEvent struct {
+	ScopeID  uint64
+	ParentID uint64
+}
+
+// DisposeFailedEvent describes a service instance that failed to dispose.
+type DisposeFailedEvent struct {
+	Descriptor ServiceDescriptor
+	ScopeID    uint64
+	Err        error
+}
+
+// combineHooks merges several hook sets into one. A field of the result is nil
+// when no hook set defines it, so disabled hooks stay free.
+func combineHooks(hooks []ContainerHooks) ContainerHooks {
+	if len(hooks) == 1 {
+		return hooks[0]
+	}
+	return ContainerHooks{
+		OnBuild: combineHook(hooks, func(h ContainerHooks) func(BuildEvent) {
+			return h.OnBuild
+		}),
+		OnResolved: combineHook(hooks, func(h ContainerHooks) func(ResolvedEvent) {
+			return h.OnResolved
+		}),
+		OnInstantiated: combineHook(hooks, func(h ContainerHooks) func(InstantiatedEvent) {
+			return h.OnInstantiated
+		}),
+		OnResolveFailed: combineHook(hooks, func(h ContainerHooks) func(ResolveFailedEvent) {
+			return h.OnResolveFailed
+		}),
+		OnScopeCreated: combineHook(hooks, func(h ContainerHooks) func(ScopeEvent) {
+			return h.OnScopeCreated
+		}),
+		OnScopeDisposed: combineHook(hooks, func(h ContainerHooks) func(ScopeEvent) {
+			return h.OnScopeDisposed
+		}),
+		OnDisposeFailed: combineHook(hooks, func(h ContainerHooks) func(DisposeFailedEvent) {
+			return h.OnDisposeFailed
+		}),
+	}
+}
+
+func combineHook[E any](hooks []ContainerHooks, selector func(ContainerHooks) func(E)) func(E) {
+	funcs := filterSlice(
+		mapSlice(hooks, selector),
+		func(hook func(E)) bool { return hook != nil })
+	switch len(funcs) {
+	case 0:
+		return nil
+	case 1:
+		return funcs[0]
+	default:
+		return func(event E) {
+			for _, hook := range funcs {
+				hook(event)
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombineHooks_Empty(t *testing.T) {
+	hooks := combineHooks(nil)
+	assert.Nil(t, hooks.OnBuild)
+	assert.Nil(t, hooks.OnResolved)
+	assert.Nil(t, hooks.OnInstantiated)
+	assert.Nil(t, hooks.OnResolveFailed)
+	assert.Nil(t, hooks.OnScopeCreated)
+	assert.Nil(t, hooks.OnScopeDisposed)
+	assert.Nil(t, hooks.OnDisposeFailed)
+}
+
+func TestCombineHooks_Many(t *testing.T) {
+	var calls []string
+	hooks := combineHooks([]ContainerHooks{
+		{OnBuild: func(BuildEvent) { calls = append(calls, "first") }},
+		{OnScopeCreated: func(ScopeEvent) { calls = append(calls, "scope") }},
+		{OnBuild: func(BuildEvent) { calls = append(calls, "second") }},
+	})
+	assert.Nil(t, hooks.OnResolved)
+
+	hooks.OnBuild(BuildEvent{})
+	hooks.OnScopeCreated(ScopeEvent{})
+	assert.Equal(t, []string{"first", "second", "scope"}, calls)
+}
+
+type testPanicDisposable struct{}
+
+func (*testPanicDisposable) Dispose() {
+	panic("cannot dispose")
+}
+
+func TestContainerHooks_Events(t *testing.T) {
+	var builds []BuildEvent
+	var resolved []ResolvedEvent
+	var instantiated []InstantiatedEvent
+	var failures []ResolveFailedEvent
+	var created, disposed []ScopeEvent
+	var disposeFailures []DisposeFailedEvent
+
+	descriptor, _ := NewSingletonStruct[testServiceInterface, testServiceStruct]()
+	failing, _ := NewInstance(&testPanicDisposable{})
+	services := NewServiceCollection().AddRange(descriptor, failing)
+	services.AddHooks(ContainerHooks{
+		OnBuild:         func(event BuildEvent) { builds = append(builds, event) },
+		OnResolved:      func(event ResolvedEvent) { resolved = append(resolved, event) },
+		OnInstantiated:  func(event InstantiatedEvent) { instantiated = append(instantiated, event) },
+		OnResolveFailed: func(event ResolveFailedEvent) { failures = append(failures, event) },
+		OnScopeCreated:  func(event ScopeEvent) { created = append(created, event) },
+		OnScopeDisposed: func(event ScopeEvent) { disposed = append(disposed, event) },
+		OnDisposeFailed: func(event DisposeFailedEvent) { disposeFailures = append(disposeFailures, event) },
+	})
+
+	root, err := services.Build()
+	assert.NoError(t, err)
+	assert.Len(t, builds, 1)
+	assert.Equal(t, 2, builds[0].Descriptors)
+	assert.NoError(t, builds[0].Err)
+	assert.Len(t, created, 1)
+	assert.Zero(t, created[0].ParentID)
+
+	scope, _ := root.CreateScope()
+	assert.Len(t, created, 2)
+	assert.Equal(t, created[0].ScopeID, created[1].ParentID)
+
+	_, _ = scope.Provider().GetService(typeOfTestServiceInterface)
+	_, _ = scope.Provider().GetService(typeOfTestServiceInterface)
+	_, _ = scope.Provider().GetService(typeOf[*testPanicDisposable]())
+	assert.Len(t, instantiated, 2)
+	assert.Same(t, descriptor, instantiated[0].Descriptor)
+	assert.Equal(t, created[0].ScopeID, instantiated[0].ScopeID)
+	assert.Equal(t, []bool{false, true, false}, mapSlice(resolved, func(event ResolvedEvent) bool {
+		return event.Cached
+	}))
+
+	_, err = scope.Provider().GetService(typeOfInt)
+	assert.Error(t, err)
+	assert.Len(t, failures, 1)
+	assert.Equal(t, typeOfInt, failures[0].ServiceType)
+	assert.Equal(t, created[1].ScopeID, failures[0].ScopeID)
+
+	scope.Dispose()
+	root.Dispose()
+	assert.Equal(t, []ScopeEvent{created[1], created[0]}, disposed)
+	assert.Len(t, disposeFailures, 1)
+	assert.Same(t, failing, disposeFailures[0].Descriptor)
+	assert.EqualError(t, disposeFailures[0].Err, "cannot dispose")
+}
@@ -0,0 +1,169 @@
+package di
+
+import (
+	"expvar"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBuckets are the upper bounds of the factory latency histogram buckets.
+var latencyBuckets = []time.Duration{
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// ExpvarMetrics is a MetricsCollector publishing container metrics through expvar.
+// Per service type counters are keyed by the service type name.
+type ExpvarMetrics struct {
+	mutex              sync.Mutex
+	root               *expvar.Map
+	resolutions        *expvar.Map
+	cacheHits          *expvar.Map
+	factoryInvocations *expvar.Map
+	factoryErrors      *expvar.Map
+	factoryLatency     *expvar.Map
+	disposeFailures    *expvar.Map
+	liveScopes         *expvar.Int
+}
+
+var _ MetricsCollector = (*ExpvarMetrics)(nil)
+
+// NewExpvarMetrics creates a new expvar metrics collector, published under the
+// given name unless it is empty. As with expvar.Publish, publishing the same
+// name twice panics.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	metrics := &ExpvarMetrics{
+		root:               new(expvar.Map).Init(),
+		resolutions:        new(expvar.Map).Init(),
+		cacheHits:          new(expvar.Map).Init(),
+		factoryInvocations: new(expvar.Map).Init(),
+		factoryErrors:      new(expvar.Map).Init(),
+		factoryLatency:     new(expvar.Map).Init(),
+		disposeFailures:    new(expvar.Map).Init(),
+		liveScopes:         new(expvar.Int),
+	}
+
+	metrics.root.Set("resolutions", metrics.resolutions)
+	metrics.root.Set("cacheHits", metrics.cacheHits)
+	metrics.root.Set("factoryInvocations", metrics.factoryInvocations)
+	metrics.root.Set("factoryErrors", metrics.factoryErrors)
+	metrics.root.Set("factoryLatency", metrics.factoryLatency)
+	metrics.root.Set("disposeFailures", metrics.disposeFailures)
+	metrics.root.Set("liveScopes", metrics.liveScopes)
+
+	if name != "" {
+		expvar.Publish(name, metrics.root)
+	}
+
+	return metrics
+}
+
+// Var returns the expvar variable holding every metric.
+func (metrics *ExpvarMetrics) Var() expvar.Var {
+	return metrics.root
+}
+
+// ServiceResolved implements MetricsCollector
+func (metrics *ExpvarMetrics) ServiceResolved(serviceType reflect.Type, cached bool) {
+	key := serviceType.String()
+	metrics.resolutions.Add(key, 1)
+	if cached {
+		metrics.cacheHits.Add(key, 1)
+	}
+}
+
+// FactoryInvoked implements MetricsCollector
+func (metrics *ExpvarMetrics) FactoryInvoked(serviceType reflect.Type, duration time.Duration, err error) {
+	key := serviceType.String()
+	metrics.factoryInvocations.Add(key, 1)
+	if err != nil {
+		metrics.factoryErrors.Add(key, 1)
+	}
+	metrics.histogram(key).observe(duration)
+}
+
+// ScopeCreated implements MetricsCollector
+func (metrics *ExpvarMetrics) ScopeCreated() {
+	metrics.liveScopes.Add(1)
+}
+
+// ScopeDisposed implements MetricsCollector
+func (metrics *ExpvarMetrics) ScopeDisposed() {
+	metrics.liveScopes.Add(-1)
+}
+
+// DisposeFailed implements MetricsCollector
+func (metrics *ExpvarMetrics) DisposeFailed(serviceType reflect.Type) {
+	metrics.disposeFailures.Add(serviceType.String(), 1)
+}
+
+func (metrics *ExpvarMetrics) histogram(key string) *latencyHistogram {
+	if histogram, ok := metrics.factoryLatency.Get(key).(*latencyHistogram); ok {
+		return histogram
+	}
+
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	if histogram, ok := metrics.factoryLatency.Get(key).(*latencyHistogram); ok {
+		return histogram
+	}
+	histogram := &latencyHistogram{
+		buckets: make([]atomic.Int64, len(latencyBuckets)+1),
+	}
+	metrics.factoryLatency.Set(key, histogram)
+	return histogram
+}
+
+// latencyHistogram is an expvar variable counting durations in latencyBuckets.
+// Bucket counts are cumulative, each one counting durations up to its bound.
+type latencyHistogram struct {
+	count   atomic.Int64
+	sum     atomic.Int64
+	buckets []atomic.Int64
+}
+
+var _ expvar.Var = (*latencyHistogram)(nil)
+
+func (histogram *latencyHistogram) observe(duration time.Duration) {
+	histogram.count.Add(1)
+	histogram.sum.Add(int64(duration))
+	index := sort.Search(len(latencyBuckets), func(i int) bool {
+		return duration <= latencyBuckets[i]
+	})
+	histogram.buckets[index].Add(1)
+}
+
+// String implements expvar.Var
+func (histogram *latencyHistogram) String() string {
+	var sb strings.Builder
+	sb.WriteString(`{"count": `)
+	sb.WriteString(strconv.FormatInt(histogram.count.Load(), 10))
+	sb.WriteString(`, "sumSeconds": `)
+	sb.WriteString(strconv.FormatFloat(time.Duration(histogram.sum.Load()).Seconds(), 'g', -1, 64))
+	sb.WriteString(`, "buckets": {`)
+	var cumulative int64
+	for i := range histogram.buckets {
+		label := "+Inf"
+		if i < len(latencyBuckets) {
+			label = latencyBuckets[i].String()
+		}
+		cumulative += histogram.buckets[i].Load()
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strconv.Quote(label))
+		sb.WriteString(": ")
+		sb.WriteString(strconv.FormatInt(cumulative, 10))
+	}
+	sb.WriteString("}}")
+	return sb.String()
+}
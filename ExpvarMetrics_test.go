@@ -0,0 +1,63 @@
+package di
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpvarMetrics(t *testing.T) {
+	metrics := NewExpvarMetrics("")
+
+	metrics.ScopeCreated()
+	metrics.ScopeCreated()
+	metrics.ScopeDisposed()
+	metrics.ServiceResolved(typeOfInt, false)
+	metrics.ServiceResolved(typeOfInt, true)
+	metrics.ServiceResolved(typeOfString, false)
+	metrics.FactoryInvoked(typeOfInt, 50*time.Microsecond, nil)
+	metrics.FactoryInvoked(typeOfString, 2*time.Second, errTestFactory)
+	metrics.DisposeFailed(typeOfString)
+
+	var actual map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(metrics.Var().String()), &actual))
+	assert.Equal(t, map[string]any{
+		"resolutions":        map[string]any{"int": 2.0, "string": 1.0},
+		"cacheHits":          map[string]any{"int": 1.0},
+		"factoryInvocations": map[string]any{"int": 1.0, "string": 1.0},
+		"factoryErrors":      map[string]any{"string": 1.0},
+		"factoryLatency": map[string]any{
+			"int": map[string]any{
+				"count":      1.0,
+				"sumSeconds": 0.00005,
+				"buckets": map[string]any{
+					"100µs": 1.0, "1ms": 1.0, "10ms": 1.0, "100ms": 1.0, "1s": 1.0, "+Inf": 1.0,
+				},
+			},
+			"string": map[string]any{
+				"count":      1.0,
+				"sumSeconds": 2.0,
+				"buckets": map[string]any{
+					"100µs": 0.0, "1ms": 0.0, "10ms": 0.0, "100ms": 0.0, "1s": 0.0, "+Inf": 1.0,
+				},
+			},
+		},
+		"disposeFailures": map[string]any{"string": 1.0},
+		"liveScopes":      1.0,
+	}, actual)
+}
+
+// testExpvarPublished counts the names published by the tests, which must be
+// unique for the tests to be run several times.
+var testExpvarPublished int
+
+func TestNewExpvarMetrics_Published(t *testing.T) {
+	testExpvarPublished++
+	name := fmt.Sprintf("di_%s_%d", t.Name(), testExpvarPublished)
+	metrics := NewExpvarMetrics(name)
+	assert.Same(t, metrics.Var(), expvar.Get(name))
+}
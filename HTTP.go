@@ -0,0 +1,83 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+var ErrNoScopeInContext = errors.New("no service scope in context")
+
+// scopeContextKey is the context key of the scope of a request.
+type scopeContextKey struct{}
+
+// ContextWithScope returns a context holding the given scope.
+func ContextWithScope(ctx context.Context, scope ServiceContainer) context.Context {
+	return context.WithValue(ctx, scopeContextKey{}, scope)
+}
+
+// ScopeFromContext returns the scope held by the given context.
+func ScopeFromContext(ctx context.Context) (ServiceContainer, bool) {
+	scope, ok := ctx.Value(scopeContextKey{}).(ServiceContainer)
+	return scope, ok
+}
+
+// GetServiceFromContext resolves a service of type T from the scope held by
+// the given context.
+func GetServiceFromContext[T any](ctx context.Context) (T, error) {
+	scope, ok := ScopeFromContext(ctx)
+	if !ok {
+		var empty T
+		return empty, ErrNoScopeInContext
+	}
+	return GetService[T](scope.Provider())
+}
+
+// ScopeMiddleware returns a middleware creating a scope from the given
+// container for each request, held by the request context, and disposing it
+// when the next handler returns or panics. Requests are answered with 503
+// Service Unavailable when no scope can be created.
+func ScopeMiddleware(container ServiceContainer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope, err := container.CreateScope()
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			defer scope.Dispose()
+
+			next.ServeHTTP(w, r.WithContext(ContextWithScope(r.Context(), scope)))
+		})
+	}
+}
+
+// ScopedHandler returns a handler serving each request with the handler of
+// type T resolved from the request scope, so that its dependencies are
+// injected per request. Requests are answered with 500 Internal Server Error
+// when the handler cannot be resolved. See ScopeMiddleware.
+func ScopedHandler[T http.Handler]() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, err := GetServiceFromContext[T](r.Context())
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// AddScopedHandler registers the struct type H as a scoped service of type
+// *H, with its fields injected, and returns the ScopedHandler serving requests
+// with it.
+func AddScopedHandler[H any, P interface {
+	*H
+	http.Handler
+}](services ServiceCollection) (http.Handler, error) {
+	descriptor, err := NewScopedStruct[P, H]()
+	if err != nil {
+		return nil, err
+	}
+	services.Add(descriptor)
+	return ScopedHandler[P](), nil
+}
@@ -0,0 +1,118 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequestGreeter struct {
+	id int
+}
+
+type testGreetingHandler struct {
+	Greeter *testRequestGreeter
+}
+
+func (handler *testGreetingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "hello from greeter %d", handler.Greeter.id)
+}
+
+func newTestRequestServices(events *[]string) ServiceCollection {
+	created := 0
+	return NewServiceCollection().Add(NewScopedFactory[*testDisposeRecorder](func(ServiceProvider) (any, error) {
+		created++
+		return &testDisposeRecorder{name: fmt.Sprintf("request %d disposed", created), disposed: events}, nil
+	})).Add(NewScopedFactory[*testRequestGreeter](func(ServiceProvider) (any, error) {
+		created++
+		return &testRequestGreeter{id: created}, nil
+	}))
+}
+
+func serveTestRequest(handler http.Handler) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	return recorder
+}
+
+func TestScopeMiddleware(t *testing.T) {
+	var events []string
+	container, err := newTestRequestServices(&events).Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	handler := ScopeMiddleware(container)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope, ok := ScopeFromContext(r.Context())
+		assert.True(t, ok)
+		assert.True(t, scope.IsScoped())
+
+		recorder, err := GetServiceFromContext[*testDisposeRecorder](r.Context())
+		assert.NoError(t, err)
+		again, err := GetService[*testDisposeRecorder](scope.Provider())
+		assert.NoError(t, err)
+		assert.Same(t, recorder, again)
+		events = append(events, "handled")
+	}))
+
+	serveTestRequest(handler)
+	serveTestRequest(handler)
+	assert.Equal(t, []string{"handled", "request 1 disposed", "handled", "request 2 disposed"}, events)
+}
+
+func TestScopeMiddleware_Panic(t *testing.T) {
+	var events []string
+	container, err := newTestRequestServices(&events).Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	handler := ScopeMiddleware(container)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := GetServiceFromContext[*testDisposeRecorder](r.Context())
+		assert.NoError(t, err)
+		panic("handler failed")
+	}))
+
+	assert.PanicsWithValue(t, "handler failed", func() { serveTestRequest(handler) })
+	assert.Equal(t, []string{"request 1 disposed"}, events)
+}
+
+func TestScopeMiddleware_DisposedContainer(t *testing.T) {
+	container, err := NewServiceCollection().Build()
+	assert.NoError(t, err)
+	container.Dispose()
+
+	handled := false
+	handler := ScopeMiddleware(container)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		handled = true
+	}))
+
+	assert.Equal(t, http.StatusServiceUnavailable, serveTestRequest(handler).Code)
+	assert.False(t, handled)
+}
+
+func TestGetServiceFromContext_NoScope(t *testing.T) {
+	_, ok := ScopeFromContext(context.Background())
+	assert.False(t, ok)
+	_, err := GetServiceFromContext[*testRequestGreeter](context.Background())
+	assert.ErrorIs(t, err, ErrNoScopeInContext)
+}
+
+func TestAddScopedHandler(t *testing.T) {
+	var events []string
+	services := newTestRequestServices(&events)
+	greeting, err := AddScopedHandler[testGreetingHandler](services)
+	assert.NoError(t, err)
+	container, err := services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	handler := ScopeMiddleware(container)(greeting)
+	assert.Equal(t, "hello from greeter 1", serveTestRequest(handler).Body.String())
+	assert.Equal(t, "hello from greeter 2", serveTestRequest(handler).Body.String())
+
+	// Without the middleware, requests have no scope to resolve handlers from
+	assert.Equal(t, http.StatusInternalServerError, serveTestRequest(greeting).Code)
+}
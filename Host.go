@@ -0,0 +1,166 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var ErrHostAlreadyStarted = errors.New("host already started")
+
+// DefaultShutdownTimeout is the time given to hosted services to stop, unless
+// set in HostOptions.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// HostOptions configures a Host.
+type HostOptions struct {
+	// ShutdownTimeout is the time given to hosted services to stop.
+	ShutdownTimeout time.Duration
+	// Signals stop the host when received by Run. Defaults to SIGINT and SIGTERM.
+	Signals []os.Signal
+}
+
+// Host runs the hosted services registered in a service collection: it starts
+// them in dependency order, and stops them in reverse order before disposing
+// the service container.
+type Host struct {
+	mutex       sync.Mutex
+	options     HostOptions
+	container   ServiceContainer
+	descriptors []ServiceDescriptor
+	order       []int
+	started     []HostedService
+	isStarted   bool
+}
+
+// NewHost builds the given service collection into a new host.
+func NewHost(services ServiceCollection, options HostOptions) (*Host, error) {
+	if options.ShutdownTimeout <= 0 {
+		options.ShutdownTimeout = DefaultShutdownTimeout
+	}
+	if len(options.Signals) == 0 {
+		options.Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	descriptors, err := sortDescriptors(services.ListDescriptors())
+	if err != nil {
+		return nil, err
+	}
+	registered := searchDescriptors(descriptors, typeOfHostedService)
+	hosted, err := sortHostedServices(descriptors, registered)
+	if err != nil {
+		return nil, err
+	}
+	// Hosted services are resolved in container order, then started in order
+	order := mapSlice(hosted, func(descriptor ServiceDescriptor) int {
+		return filterSliceIndices(registered, func(other ServiceDescriptor) bool {
+			return other == descriptor
+		})[0]
+	})
+
+	container, err := services.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Host{
+		options:     options,
+		container:   container,
+		descriptors: hosted,
+		order:       order,
+	}, nil
+}
+
+// Container returns the service container of the host.
+func (host *Host) Container() ServiceContainer {
+	return host.container
+}
+
+// Provider returns the service provider of the host.
+func (host *Host) Provider() ServiceProvider {
+	return host.container.Provider()
+}
+
+// Start resolves and starts the hosted services in dependency order.
+// When a hosted service fails to start, the ones already started are stopped.
+func (host *Host) Start(ctx context.Context) error {
+	host.mutex.Lock()
+	defer host.mutex.Unlock()
+
+	if host.isStarted {
+		return ErrHostAlreadyStarted
+	}
+	host.isStarted = true
+
+	services, err := host.resolveHostedServices()
+	if err != nil {
+		return err
+	}
+
+	for i, service := range services {
+		if err := service.Start(ctx); err != nil {
+			startErr := fmt.Errorf("hosted service %s failed to start: %w", host.descriptors[i].Factory().DisplayName(), err)
+			stopCtx, cancel := context.WithTimeout(context.Background(), host.options.ShutdownTimeout)
+			defer cancel()
+			return errors.Join(startErr, host.stopStarted(stopCtx))
+		}
+		host.started = append(host.started, service)
+	}
+
+	return nil
+}
+
+// resolveHostedServices resolves the hosted services, in the order of host.descriptors.
+func (host *Host) resolveHostedServices() ([]HostedService, error) {
+	resolved, err := GetService[[]HostedService](host.Provider())
+	if err != nil {
+		return nil, err
+	}
+
+	return mapSlice(host.order, func(index int) HostedService {
+		return resolved[index]
+	}), nil
+}
+
+// Stop stops the started hosted services in reverse order, then disposes the
+// service container.
+func (host *Host) Stop(ctx context.Context) error {
+	host.mutex.Lock()
+	defer host.mutex.Unlock()
+
+	err := host.stopStarted(ctx)
+	host.container.Dispose()
+	return err
+}
+
+func (host *Host) stopStarted(ctx context.Context) error {
+	var errs []error
+	for i := len(host.started) - 1; i >= 0; i-- {
+		if err := host.started[i].Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("hosted service %s failed to stop: %w", host.descriptors[i].Factory().DisplayName(), err))
+		}
+	}
+	host.started = nil
+	return errors.Join(errs...)
+}
+
+// Run starts the host, waits until the context is done or a stop signal is
+// received, then stops the host within the shutdown timeout.
+func (host *Host) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, host.options.Signals...)
+	defer stop()
+
+	startErr := host.Start(ctx)
+	if startErr == nil {
+		<-ctx.Done()
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), host.options.ShutdownTimeout)
+	defer cancel()
+	return errors.Join(startErr, host.Stop(stopCtx))
+}
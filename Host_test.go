@@ -0,0 +1,91 @@
+package di
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHost(t *testing.T, events *[]string) *Host {
+	services := NewServiceCollection()
+	addTestHostedServices(services, events)
+	services.Add(NewSingletonFactory[*testDisposeRecorder](func(ServiceProvider) (any, error) {
+		return &testDisposeRecorder{name: "container disposed", disposed: events}, nil
+	}))
+	AddHostedService[*testHostedServer](services)
+	AddHostedService[*testHostedDatabase](services)
+	AddHostedService[*testHostedCache](services)
+
+	host, err := NewHost(services, HostOptions{ShutdownTimeout: time.Second})
+	assert.NoError(t, err)
+	_, err = GetService[*testDisposeRecorder](host.Provider())
+	assert.NoError(t, err)
+	return host
+}
+
+func TestHost_StartStop(t *testing.T) {
+	var events []string
+	host := newTestHost(t, &events)
+
+	assert.NoError(t, host.Start(context.Background()))
+	assert.Equal(t, ErrHostAlreadyStarted, host.Start(context.Background()))
+	assert.NoError(t, host.Stop(context.Background()))
+
+	assert.Equal(t, []string{
+		"start database", "start cache", "start server",
+		"stop server", "stop cache", "stop database",
+		"container disposed",
+	}, events)
+	assert.True(t, host.Container().IsDisposed())
+}
+
+func TestHost_StartFailure(t *testing.T) {
+	var events []string
+	host := newTestHost(t, &events)
+	cache, _ := GetService[*testHostedCache](host.Provider())
+	cache.failOn = "start"
+
+	err := host.Start(context.Background())
+	assert.ErrorIs(t, err, errTestFactory)
+	assert.ErrorContains(t, err, "hosted service HostedService[*di.testHostedCache] failed to start")
+	assert.Equal(t, []string{"start database", "start cache", "stop database"}, events)
+}
+
+func TestHost_StopFailure(t *testing.T) {
+	var events []string
+	host := newTestHost(t, &events)
+	cache, _ := GetService[*testHostedCache](host.Provider())
+	cache.failOn = "stop"
+
+	assert.NoError(t, host.Start(context.Background()))
+	err := host.Stop(context.Background())
+	assert.ErrorIs(t, err, errTestFactory)
+	assert.ErrorContains(t, err, "hosted service HostedService[*di.testHostedCache] failed to stop")
+	assert.Equal(t, "container disposed", events[len(events)-1])
+}
+
+func TestHost_RunUntilCanceled(t *testing.T) {
+	var events []string
+	host := newTestHost(t, &events)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() { done <- host.Run(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		return host.Provider().GetServiceInfo(typeOf[*testHostedServer]()).IsInstantiated
+	}, time.Second, time.Millisecond)
+	cancel()
+
+	assert.NoError(t, <-done)
+	assert.Equal(t, "container disposed", events[len(events)-1])
+}
+
+func TestNewHost_InvalidServices(t *testing.T) {
+	descriptor, _ := NewSingletonStructPtr[testStructWithDependency]()
+	host, err := NewHost(NewServiceCollection().Add(descriptor), HostOptions{})
+	assert.Nil(t, host)
+	assert.Error(t, err)
+}
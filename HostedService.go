@@ -0,0 +1,125 @@
+package di
+
+import (
+	"context"
+	"reflect"
+)
+
+// HostedService is a service started and stopped by a Host.
+type HostedService interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+var typeOfHostedService = typeOf[HostedService]()
+
+// AddHostedServiceForType registers the service of the given type as a hosted
+// service. The service type must be registered separately, and must implement
+// HostedService. Hosted services are started after the hosted services
+// registered for the types they depend on, directly or not.
+func AddHostedServiceForType(services ServiceCollection, serviceType reflect.Type) ServiceCollection {
+	factory := NewFactoryWith(
+		"HostedService["+serviceType.String()+"]",
+		[]reflect.Type{serviceType},
+		func(provider ServiceProvider) (any, error) {
+			service, err := provider.GetService(serviceType)
+			if err != nil {
+				return nil, err
+			}
+			hosted, ok := service.(HostedService)
+			if !ok {
+				return nil, ErrInvalidServiceType
+			}
+			return hosted, nil
+		})
+
+	return services.Add(NewTransientServiceFactory[HostedService](&hostedServiceFactory{
+		ServiceFactory: factory,
+		serviceType:    serviceType,
+	}))
+}
+
+// AddHostedService registers the service of type T as a hosted service.
+// See AddHostedServiceForType.
+func AddHostedService[T HostedService](services ServiceCollection) ServiceCollection {
+	return AddHostedServiceForType(services, typeOf[T]())
+}
+
+// hostedServiceFactory marks the factories forwarding a hosted service to
+// the service type it was registered for.
+type hostedServiceFactory struct {
+	ServiceFactory
+	serviceType reflect.Type
+}
+
+// sortHostedServices orders hosted service descriptors so that each one comes
+// after the ones it depends on, keeping the registration order otherwise.
+func sortHostedServices(
+	descriptors []ServiceDescriptor,
+	hosted []ServiceDescriptor,
+) ([]ServiceDescriptor, error) {
+	reached := mapSlice(hosted, func(descriptor ServiceDescriptor) map[reflect.Type]bool {
+		return reachableRequirements(descriptors, descriptor)
+	})
+
+	dependsOn := func(i, j int) bool {
+		factory, ok := hosted[j].Factory().(*hostedServiceFactory)
+		return ok && i != j && reached[i][factory.serviceType]
+	}
+
+	sorted := make([]ServiceDescriptor, 0, len(hosted))
+	done := make([]bool, len(hosted))
+	for len(sorted) < len(hosted) {
+		next := -1
+		for i := range hosted {
+			if done[i] {
+				continue
+			}
+			ready := allSlice(rangeSlice(0, len(hosted)), func(j int) bool {
+				return done[j] || !dependsOn(i, j)
+			})
+			if ready {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			remaining := filterSlice(rangeSlice(0, len(hosted)), func(i int) bool { return !done[i] })
+			return nil, newServiceResolutionError(
+				mapSlice(remaining, func(i int) ServiceDescriptor { return hosted[i] }),
+				typeOfHostedService,
+				ErrCircularDependency)
+		}
+		done[next] = true
+		sorted = append(sorted, hosted[next])
+	}
+
+	return sorted, nil
+}
+
+// reachableRequirements returns every type required by a descriptor, directly
+// or through the requirements of the descriptors it requires.
+func reachableRequirements(descriptors []ServiceDescriptor, descriptor ServiceDescriptor) map[reflect.Type]bool {
+	reached := map[reflect.Type]bool{}
+	pending := mapSlice(descriptor.Factory().Requirements(), unwrapMetaRequirement)
+
+	for len(pending) > 0 {
+		requirement := pending[0]
+		pending = pending[1:]
+		if reached[requirement] {
+			continue
+		}
+		reached[requirement] = true
+
+		if requirement.Kind() == reflect.Slice {
+			for _, required := range searchDescriptors(descriptors, requirement.Elem()) {
+				pending = append(pending, mapSlice(required.Factory().Requirements(), unwrapMetaRequirement)...)
+			}
+			pending = append(pending, requirement.Elem())
+		} else if required := searchDescriptor(descriptors, requirement); required != nil {
+			pending = append(pending, mapSlice(required.Factory().Requirements(), unwrapMetaRequirement)...)
+		}
+	}
+
+	return reached
+}
@@ -0,0 +1,116 @@
+package di
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testHostedService struct {
+	name   string
+	events *[]string
+	failOn string
+}
+
+func (service *testHostedService) Start(ctx context.Context) error {
+	*service.events = append(*service.events, "start "+service.name)
+	if service.failOn == "start" {
+		return errTestFactory
+	}
+	return nil
+}
+
+func (service *testHostedService) Stop(ctx context.Context) error {
+	*service.events = append(*service.events, "stop "+service.name)
+	if service.failOn == "stop" {
+		return errTestFactory
+	}
+	return nil
+}
+
+type testHostedDatabase struct{ testHostedService }
+type testHostedCache struct {
+	testHostedService
+	Database *testHostedDatabase
+}
+type testHostedServer struct {
+	testHostedService
+	Cache *testHostedCache
+}
+
+func addTestHostedServices(services ServiceCollection, events *[]string) {
+	services.Add(NewSingletonServiceFactory[*testHostedServer](NewFactoryWith(
+		"server",
+		[]reflect.Type{typeOf[*testHostedCache]()},
+		func(provider ServiceProvider) (any, error) {
+			cache, err := GetService[*testHostedCache](provider)
+			return &testHostedServer{testHostedService{"server", events, ""}, cache}, err
+		})))
+	services.Add(NewSingletonServiceFactory[*testHostedCache](NewFactoryWith(
+		"cache",
+		[]reflect.Type{typeOf[*testHostedDatabase]()},
+		func(provider ServiceProvider) (any, error) {
+			database, err := GetService[*testHostedDatabase](provider)
+			return &testHostedCache{testHostedService{"cache", events, ""}, database}, err
+		})))
+	services.Add(NewSingletonFactory[*testHostedDatabase](func(ServiceProvider) (any, error) {
+		return &testHostedDatabase{testHostedService{"database", events, ""}}, nil
+	}))
+}
+
+func TestAddHostedService(t *testing.T) {
+	var events []string
+	services := NewServiceCollection()
+	addTestHostedServices(services, &events)
+	AddHostedService[*testHostedDatabase](services)
+
+	descriptor := services.FindFirstDescriptorForType(typeOfHostedService)
+	assert.Equal(t, Transient, descriptor.Lifetime())
+	assert.Equal(t, "HostedService[*di.testHostedDatabase]", descriptor.Factory().DisplayName())
+	assert.Equal(t, []reflect.Type{typeOf[*testHostedDatabase]()}, descriptor.Factory().Requirements())
+
+	container, _ := services.Build()
+	hosted, err := GetService[HostedService](container.Provider())
+	assert.NoError(t, err)
+	database, _ := GetService[*testHostedDatabase](container.Provider())
+	assert.Same(t, database, hosted)
+}
+
+func TestSortHostedServices(t *testing.T) {
+	var events []string
+	services := NewServiceCollection()
+	addTestHostedServices(services, &events)
+	AddHostedService[*testHostedServer](services)
+	AddHostedService[*testHostedDatabase](services)
+	AddHostedService[*testHostedCache](services)
+
+	descriptors := services.ListDescriptors()
+	hosted := searchDescriptors(descriptors, typeOfHostedService)
+	sorted, err := sortHostedServices(descriptors, hosted)
+	assert.NoError(t, err)
+	assert.Equal(t, []ServiceDescriptor{hosted[1], hosted[2], hosted[0]}, sorted)
+}
+
+type testHostedCycleA struct {
+	testHostedService
+	B *testHostedCycleB
+}
+type testHostedCycleB struct {
+	testHostedService
+	A *testHostedCycleA
+}
+
+func TestSortHostedServices_Circular(t *testing.T) {
+	services := NewServiceCollection()
+	descriptorA, _ := NewSingletonStructPtr[testHostedCycleA]()
+	descriptorB, _ := NewSingletonStructPtr[testHostedCycleB]()
+	services.AddRange(descriptorA, descriptorB)
+	AddHostedService[*testHostedCycleA](services)
+	AddHostedService[*testHostedCycleB](services)
+
+	descriptors := services.ListDescriptors()
+	_, err := sortHostedServices(descriptors, searchDescriptors(descriptors, typeOfHostedService))
+	assert.ErrorIs(t, err, ErrCircularDependency)
+}
@@ -9,6 +9,12 @@ const (
 	Singleton
 	Scoped
 	Transient
+	// Pooled services are taken from a bounded pool per descriptor, and
+	// returned to it when the scope they were resolved from is disposed.
+	Pooled
+	// PerResolution services are instantiated once for each top-level
+	// request, and shared by the services resolved for it.
+	PerResolution
 )
 
 var _ fmt.Stringer = Singleton
@@ -21,7 +27,14 @@ func (lifetime Lifetime) String() string {
 		return "Scoped"
 	case Transient:
 		return "Transient"
+	case Pooled:
+		return "Pooled"
+	case PerResolution:
+		return "PerResolution"
 	default:
+		if custom, ok := lookupLifetime(lifetime); ok {
+			return custom.name
+		}
 		return "Unknown"
 	}
 }
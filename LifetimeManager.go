@@ -0,0 +1,97 @@
+package di
+
+import "sync"
+
+// LifetimeManager manages the instances of the services of a custom
+// lifetime registered with RegisterLifetime, such as a lifetime per tenant or
+// per connection. A manager is created for each root container.
+type LifetimeManager interface {
+	// GetOrCreate returns the instance of the requested descriptor, calling
+	// create to instantiate it when the manager holds none for the request.
+	// Instances created by create are owned by the manager, which disposes
+	// them. Each instance is created in its own scope, inheriting the values
+	// supplied to the requesting scope, and disposing the instance disposes
+	// its dependencies along with it.
+	GetOrCreate(request LifetimeRequest, create func() (ServiceInstance, error)) (any, error)
+	// Dispose disposes the instances held by the manager. It is called once
+	// the root container is disposed.
+	Dispose()
+}
+
+// LifetimeRequest is the request of a service of a custom lifetime.
+type LifetimeRequest struct {
+	// Descriptor is the descriptor of the requested service.
+	Descriptor ServiceDescriptor
+	// Scope is the container or scope the service is requested from.
+	Scope ServiceContainer
+	// Provider resolves services as the requested service's factory does,
+	// for instance to get the scope values to key instances with.
+	Provider ServiceProvider
+}
+
+// LifetimeRules tells how services of a custom lifetime depend on services
+// of other lifetimes. Build fails on descriptors breaking them.
+type LifetimeRules struct {
+	// CanRequire tells whether services of the custom lifetime can require
+	// services of the given lifetime, directly or through transient services.
+	// Every lifetime can be required when CanRequire is nil.
+	CanRequire func(lifetime Lifetime) bool
+	// Capturable allows singleton and pooled services to require services of
+	// the custom lifetime, keeping the instance they get for good.
+	Capturable bool
+}
+
+// firstCustomLifetime is the first Lifetime value given by RegisterLifetime.
+const firstCustomLifetime Lifetime = 256
+
+// customLifetime is a lifetime registered with RegisterLifetime.
+type customLifetime struct {
+	name       string
+	rules      LifetimeRules
+	newManager func() LifetimeManager
+}
+
+var customLifetimes = struct {
+	sync.RWMutex
+	registered []customLifetime
+}{}
+
+// RegisterLifetime registers a custom lifetime with the given name, whose
+// services are validated with the given rules, and managed by a manager
+// created by newManager for each root container. It returns the new Lifetime
+// value to create descriptors with. Lifetimes are meant to be registered
+// once, when initializing package variables.
+func RegisterLifetime(name string, rules LifetimeRules, newManager func() LifetimeManager) Lifetime {
+	if newManager == nil {
+		panic("di: RegisterLifetime with nil manager function")
+	}
+
+	customLifetimes.Lock()
+	defer customLifetimes.Unlock()
+	customLifetimes.registered = append(customLifetimes.registered, customLifetime{
+		name:       name,
+		rules:      rules,
+		newManager: newManager,
+	})
+	return firstCustomLifetime + Lifetime(len(customLifetimes.registered)-1)
+}
+
+// lookupLifetime returns the registration of a custom lifetime.
+func lookupLifetime(lifetime Lifetime) (customLifetime, bool) {
+	customLifetimes.RLock()
+	defer customLifetimes.RUnlock()
+	index := int(lifetime - firstCustomLifetime)
+	if index < 0 || index >= len(customLifetimes.registered) {
+		return customLifetime{}, false
+	}
+	return customLifetimes.registered[index], true
+}
+
+// isKnownLifetime tells whether a lifetime is built in or registered.
+func isKnownLifetime(lifetime Lifetime) bool {
+	if lifetime >= Singleton && lifetime <= PerResolution {
+		return true
+	}
+	_, ok := lookupLifetime(lifetime)
+	return ok
+}
@@ -0,0 +1,233 @@
+package di
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTenant string
+
+type testTenantService struct {
+	tenant   testTenant
+	disposed *[]string
+}
+
+func (service *testTenantService) Dispose() {
+	*service.disposed = append(*service.disposed, string(service.tenant))
+}
+
+type testTenantConsumer struct {
+	Service *testTenantService
+}
+
+type testTenantKey struct {
+	descriptor ServiceDescriptor
+	tenant     testTenant
+}
+
+// testTenantManager keeps an instance per tenant, supplied as a scope value.
+type testTenantManager struct {
+	mutex     sync.Mutex
+	instances map[testTenantKey]ServiceInstance
+}
+
+func (manager *testTenantManager) GetOrCreate(request LifetimeRequest, create func() (ServiceInstance, error)) (any, error) {
+	tenant, err := GetService[testTenant](request.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	key := testTenantKey{descriptor: request.Descriptor, tenant: tenant}
+	if instance, ok := manager.instances[key]; ok {
+		return instance.Instance, nil
+	}
+	instance, err := create()
+	if err != nil {
+		return nil, err
+	}
+	manager.instances[key] = instance
+	return instance.Instance, nil
+}
+
+func (manager *testTenantManager) Dispose() {
+	for _, instance := range manager.instances {
+		if instance.Disposable != nil {
+			instance.Disposable.Dispose()
+		}
+	}
+}
+
+func newTestTenantManager() LifetimeManager {
+	return &testTenantManager{instances: map[testTenantKey]ServiceInstance{}}
+}
+
+var testPerTenant = RegisterLifetime("PerTenant", LifetimeRules{
+	CanRequire: func(lifetime Lifetime) bool { return lifetime != Scoped },
+}, newTestTenantManager)
+
+var testPerTenantCapturable = RegisterLifetime("PerTenantCapturable", LifetimeRules{Capturable: true}, newTestTenantManager)
+
+func newTestTenantServices(disposed *[]string) ServiceCollection {
+	services := AddScopeSupplied[testTenant](NewServiceCollection())
+	return services.Add(NewDescriptor[*testTenantService](testPerTenant, NewFactory(
+		func(provider ServiceProvider) (any, error) {
+			tenant, err := GetService[testTenant](provider)
+			if err != nil {
+				return nil, err
+			}
+			return &testTenantService{tenant: tenant, disposed: disposed}, nil
+		})))
+}
+
+func resolveTestTenantService(t *testing.T, container ServiceContainer, tenant testTenant) *testTenantService {
+	scope, err := container.CreateScopeWith(NewScopeValue(tenant))
+	assert.NoError(t, err)
+	defer scope.Dispose()
+	service, err := GetService[*testTenantService](scope.Provider())
+	assert.NoError(t, err)
+	return service
+}
+
+func TestRegisterLifetime(t *testing.T) {
+	assert.GreaterOrEqual(t, testPerTenant, firstCustomLifetime)
+	assert.Equal(t, "PerTenant", testPerTenant.String())
+	assert.Equal(t, "Unknown", (testPerTenant + 1000).String())
+
+	assert.Panics(t, func() { RegisterLifetime("Nil", LifetimeRules{}, nil) })
+}
+
+func TestLifetimeManager(t *testing.T) {
+	var disposed []string
+	container, err := newTestTenantServices(&disposed).Build()
+	assert.NoError(t, err)
+
+	acme := resolveTestTenantService(t, container, "acme")
+	assert.Same(t, acme, resolveTestTenantService(t, container, "acme"))
+	globex := resolveTestTenantService(t, container, "globex")
+	assert.NotSame(t, acme, globex)
+	assert.Equal(t, testTenant("globex"), globex.tenant)
+	assert.Empty(t, disposed)
+
+	other, err := newTestTenantServices(&disposed).Build()
+	assert.NoError(t, err)
+	assert.NotSame(t, acme, resolveTestTenantService(t, other, "acme"))
+	other.Dispose()
+	assert.Equal(t, []string{"acme"}, disposed)
+
+	container.Dispose()
+	assert.ElementsMatch(t, []string{"acme", "acme", "globex"}, disposed)
+}
+
+type testTenantConnection struct {
+	disposed bool
+}
+
+func (connection *testTenantConnection) Dispose() {
+	connection.disposed = true
+}
+
+type testTenantClient struct {
+	Connection *testTenantConnection
+}
+
+func TestLifetimeManager_DependenciesOutliveScope(t *testing.T) {
+	client, _ := NewStructFactory[testTenantClient]()
+	container, err := AddScopeSupplied[testTenant](NewServiceCollection()).
+		Add(NewDescriptor[*testTenantClient](testPerTenant, client)).
+		Add(NewTransientFactory[*testTenantConnection](func(ServiceProvider) (any, error) {
+			return &testTenantConnection{}, nil
+		})).
+		Build()
+	assert.NoError(t, err)
+
+	resolve := func() *testTenantClient {
+		scope, err := container.CreateScopeWith(NewScopeValue[testTenant]("acme"))
+		assert.NoError(t, err)
+		defer scope.Dispose()
+		resolved, err := GetService[*testTenantClient](scope.Provider())
+		assert.NoError(t, err)
+		return resolved
+	}
+	acme := resolve()
+	assert.False(t, acme.Connection.disposed)
+	assert.Same(t, acme, resolve())
+	assert.False(t, acme.Connection.disposed)
+
+	container.Dispose()
+	assert.True(t, acme.Connection.disposed)
+}
+
+func TestLifetimeManager_Errors(t *testing.T) {
+	var disposed []string
+	container, err := newTestTenantServices(&disposed).Build()
+	assert.NoError(t, err)
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+	defer scope.Dispose()
+	_, err = GetService[*testTenantService](scope.Provider())
+	assert.ErrorIs(t, err, ErrScopeValueNotSupplied)
+
+	container.Dispose()
+	_, err = GetService[*testTenantService](scope.Provider())
+	assert.ErrorIs(t, err, ErrServiceContainerDisposed)
+}
+
+func TestLifetimeManager_Validation(t *testing.T) {
+	consumer, _ := NewStructFactory[testTenantConsumer]()
+	_, err := NewServiceCollection().
+		Add(NewDescriptor[*testTenantConsumer](testPerTenant, consumer)).
+		Add(NewScopedFactory[*testTenantService](func(ServiceProvider) (any, error) {
+			return &testTenantService{}, nil
+		})).
+		Build()
+	assert.ErrorContains(t, err, "[PerTenant] *di.testTenantConsumer")
+	assert.ErrorContains(t, err, "=(invalid)=> [Scoped] *di.testTenantService")
+
+	_, err = newTestTenantServices(&[]string{}).
+		Add(NewDescriptor[*testTenantConsumer](testPerTenant, consumer)).
+		Build()
+	assert.NoError(t, err)
+}
+
+func TestLifetimeManager_Captured(t *testing.T) {
+	for _, lifetime := range []Lifetime{Singleton, Pooled} {
+		consumer, _ := NewStructFactory[testTenantConsumer]()
+		_, err := newTestTenantServices(&[]string{}).
+			Add(NewDescriptor[*testTenantConsumer](lifetime, consumer)).
+			Build()
+		assert.ErrorContains(t, err, "=(invalid)=> [PerTenant] *di.testTenantService")
+
+		_, err = NewServiceCollection().
+			Add(NewDescriptor[*testTenantConsumer](lifetime, consumer)).
+			Add(NewDescriptor[*testTenantService](testPerTenantCapturable, NewFactory(
+				func(ServiceProvider) (any, error) {
+					return &testTenantService{}, nil
+				}))).
+			Build()
+		assert.NoError(t, err)
+	}
+
+	consumer, _ := NewScopedStructPtr[testTenantConsumer]()
+	_, err := newTestTenantServices(&[]string{}).Add(consumer).Build()
+	assert.NoError(t, err)
+}
+
+func TestLifetimeManager_UnknownLifetime(t *testing.T) {
+	for _, lifetime := range []Lifetime{UnknownLifetime, PerResolution + 1, testPerTenant + 1000} {
+		services := NewServiceCollection().Add(NewDescriptor[*testTenantService](lifetime, NewFactory(
+			func(ServiceProvider) (any, error) {
+				return &testTenantService{}, nil
+			})))
+		_, err := services.Build()
+		assert.ErrorIs(t, err, ErrUnknownLifetime)
+		assert.ErrorContains(t, err, "*di.testTenantService")
+
+		_, err = services.BuildWithOptions(BuildOptions{SkipValidation: true})
+		assert.ErrorIs(t, err, ErrUnknownLifetime)
+	}
+}
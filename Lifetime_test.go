@@ -11,5 +11,7 @@ func TestLifetimeString(t *testing.T) {
 	assert.Equal(t, "Singleton", Singleton.String())
 	assert.Equal(t, "Scoped", Scoped.String())
 	assert.Equal(t, "Transient", Transient.String())
+	assert.Equal(t, "Pooled", Pooled.String())
+	assert.Equal(t, "PerResolution", PerResolution.String())
 	assert.Equal(t, "Unknown", (Lifetime(-1)).String())
 }
\ No newline at end of file
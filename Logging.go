@@ -0,0 +1,99 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// LoggingOptions configures the container activity logged by NewLoggingHooks.
+type LoggingOptions struct {
+	// SlowFactoryThreshold is the factory duration from which a warning is logged.
+	// Zero disables slow factory warnings.
+	SlowFactoryThreshold time.Duration
+}
+
+// NewLoggingHooks returns container hooks logging container activity to the given logger.
+// Validation results and failures are logged at info and error levels, while
+// instantiations and scopes are logged at debug level.
+func NewLoggingHooks(logger *slog.Logger, options LoggingOptions) ContainerHooks {
+	ctx := context.Background()
+
+	return ContainerHooks{
+		OnBuild: func(event BuildEvent) {
+			if event.Err != nil {
+				logger.LogAttrs(ctx, slog.LevelError, "service collection validation failed",
+					slog.Int("descriptors", event.Descriptors),
+					slog.Duration("duration", event.Duration),
+					slog.String("error", event.Err.Error()))
+				return
+			}
+			logger.LogAttrs(ctx, slog.LevelInfo, "service collection validated",
+				slog.Int("descriptors", event.Descriptors),
+				slog.Duration("duration", event.Duration))
+		},
+
+		OnInstantiated: func(event InstantiatedEvent) {
+			if event.Err != nil {
+				return
+			}
+			slow := options.SlowFactoryThreshold > 0 && event.Duration >= options.SlowFactoryThreshold
+			level := slog.LevelDebug
+			message := "service instantiated"
+			if slow {
+				level = slog.LevelWarn
+				message = "slow service factory"
+			}
+			if !logger.Enabled(ctx, level) {
+				return
+			}
+			logger.LogAttrs(ctx, level, message,
+				slog.String("service", event.Descriptor.ServiceType().String()),
+				slog.String("lifetime", event.Descriptor.Lifetime().String()),
+				slog.String("factory", event.Descriptor.Factory().DisplayName()),
+				slog.Duration("duration", event.Duration),
+				slog.Uint64("scope", event.ScopeID))
+		},
+
+		OnResolveFailed: func(event ResolveFailedEvent) {
+			attrs := []slog.Attr{
+				slog.String("service", fmt.Sprint(event.ServiceType)),
+				slog.Uint64("scope", event.ScopeID),
+			}
+			var resolutionErr *ServiceResolutionError
+			if errors.As(event.Err, &resolutionErr) && len(resolutionErr.Chain) > 0 {
+				attrs = append(attrs, slog.String("path", requestChainString(resolutionErr.Chain)))
+			}
+			attrs = append(attrs, slog.String("error", event.Err.Error()))
+			logger.LogAttrs(ctx, slog.LevelError, "service resolution failed", attrs...)
+		},
+
+		OnScopeCreated: func(event ScopeEvent) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "scope created",
+				slog.Uint64("scope", event.ScopeID),
+				slog.Uint64("parent", event.ParentID))
+		},
+
+		OnScopeDisposed: func(event ScopeEvent) {
+			logger.LogAttrs(ctx, slog.LevelDebug, "scope disposed",
+				slog.Uint64("scope", event.ScopeID),
+				slog.Uint64("parent", event.ParentID))
+		},
+
+		OnDisposeFailed: func(event DisposeFailedEvent) {
+			logger.LogAttrs(ctx, slog.LevelError, "service disposal failed",
+				slog.String("service", event.Descriptor.ServiceType().String()),
+				slog.String("lifetime", event.Descriptor.Lifetime().String()),
+				slog.Uint64("scope", event.ScopeID),
+				slog.String("error", event.Err.Error()))
+		},
+	}
+}
+
+// AddLogging registers hooks logging the activity of containers built from
+// the given collection.
+func AddLogging(services ServiceCollection, logger *slog.Logger, options LoggingOptions) ServiceCollection {
+	return services.AddHooks(NewLoggingHooks(logger, options))
+}
@@ -0,0 +1,112 @@
+package di
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger(level slog.Level) (*slog.Logger, *bytes.Buffer) {
+	buffer := &bytes.Buffer{}
+	handler := slog.NewTextHandler(buffer, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey || attr.Key == "duration" {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	return slog.New(handler), buffer
+}
+
+func TestAddLogging_Build(t *testing.T) {
+	logger, buffer := newTestLogger(slog.LevelInfo)
+	services := NewServiceCollection()
+	AddLogging(services, logger, LoggingOptions{})
+
+	_, err := services.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "level=INFO msg=\"service collection validated\" descriptors=0\n", buffer.String())
+}
+
+func TestAddLogging_BuildFailure(t *testing.T) {
+	logger, buffer := newTestLogger(slog.LevelInfo)
+	descriptor, _ := NewSingletonStructPtr[testStructWithDependency]()
+	services := NewServiceCollection().Add(descriptor)
+	AddLogging(services, logger, LoggingOptions{})
+
+	_, err := services.Build()
+	assert.Error(t, err)
+	assert.Contains(t, buffer.String(), "level=ERROR msg=\"service collection validation failed\" descriptors=1 error=")
+}
+
+func TestAddLogging_Instantiation(t *testing.T) {
+	logger, buffer := newTestLogger(slog.LevelDebug)
+	descriptor, _ := NewSingletonStruct[testServiceInterface, testServiceStruct]()
+	services := NewServiceCollection().Add(descriptor)
+	AddLogging(services, logger, LoggingOptions{})
+
+	container, _ := services.Build()
+	buffer.Reset()
+
+	_, err := container.Provider().GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+	assert.Contains(t, buffer.String(),
+		"level=DEBUG msg=\"service instantiated\" service=di.testServiceInterface lifetime=Singleton factory=testServiceStruct scope=")
+}
+
+func TestAddLogging_SlowFactory(t *testing.T) {
+	logger, buffer := newTestLogger(slog.LevelWarn)
+	services := NewServiceCollection().Add(
+		NewSingletonFactory[testServiceInterface](func(ServiceProvider) (any, error) {
+			time.Sleep(2 * time.Millisecond)
+			return &testServiceStruct{}, nil
+		}))
+	AddLogging(services, logger, LoggingOptions{SlowFactoryThreshold: time.Millisecond})
+
+	container, _ := services.Build()
+	_, err := container.Provider().GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+	assert.Contains(t, buffer.String(), "level=WARN msg=\"slow service factory\" service=di.testServiceInterface")
+}
+
+func TestAddLogging_ResolveFailure(t *testing.T) {
+	withoutCallSites(t)
+	logger, buffer := newTestLogger(slog.LevelInfo)
+	descriptor, _ := NewTransientStructPtr[testStructWithDependency]()
+	services := NewServiceCollection().AddRange(
+		descriptor,
+		NewTransientFactory[testServiceInterface](func(ServiceProvider) (any, error) {
+			return nil, errTestFactory
+		}))
+	AddLogging(services, logger, LoggingOptions{})
+
+	container, _ := services.Build()
+	buffer.Reset()
+
+	_, err := container.Provider().GetService(typeOf[*testStructWithDependency]())
+	assert.Error(t, err)
+	assert.Contains(t, buffer.String(),
+		"level=ERROR msg=\"service resolution failed\" service=*di.testStructWithDependency scope=")
+	assert.Contains(t, buffer.String(), "path=\"[Transient] *di.testStructWithDependency\"")
+}
+
+func TestAddLogging_DisposeFailure(t *testing.T) {
+	logger, buffer := newTestLogger(slog.LevelInfo)
+	descriptor, _ := NewInstance(&testPanicDisposable{})
+	services := NewServiceCollection().Add(descriptor)
+	AddLogging(services, logger, LoggingOptions{})
+
+	container, _ := services.Build()
+	_, _ = container.Provider().GetService(typeOf[*testPanicDisposable]())
+	buffer.Reset()
+
+	container.Dispose()
+	assert.Contains(t, buffer.String(),
+		"level=ERROR msg=\"service disposal failed\" service=*di.testPanicDisposable lifetime=Singleton scope=")
+	assert.Contains(t, buffer.String(), "error=\"cannot dispose\"")
+}
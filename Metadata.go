@@ -0,0 +1,187 @@
+package di
+
+import (
+	"reflect"
+
+	"golang.org/x/exp/slices"
+)
+
+// Metadata holds the values and tags attached to a service descriptor.
+// The zero value holds nothing.
+type Metadata struct {
+	values map[string]any
+	tags   []string
+}
+
+// Get returns the value attached with the given key.
+func (metadata Metadata) Get(key string) (any, bool) {
+	value, ok := metadata.values[key]
+	return value, ok
+}
+
+// Keys returns the sorted keys of the attached values.
+func (metadata Metadata) Keys() []string {
+	keys := make([]string, 0, len(metadata.values))
+	for key := range metadata.values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Tags returns the attached tags.
+func (metadata Metadata) Tags() []string {
+	return cloneSlice(metadata.tags)
+}
+
+// HasTag tells whether the given tag is attached.
+func (metadata Metadata) HasTag(tag string) bool {
+	return slices.Contains(metadata.tags, tag)
+}
+
+// with returns a copy of the metadata with the given value attached.
+func (metadata Metadata) with(key string, value any) Metadata {
+	values := make(map[string]any, len(metadata.values)+1)
+	for k, v := range metadata.values {
+		values[k] = v
+	}
+	values[key] = value
+	return Metadata{values: values, tags: metadata.tags}
+}
+
+// withTags returns a copy of the metadata with the given tags attached.
+func (metadata Metadata) withTags(tags ...string) Metadata {
+	merged := cloneSlice(metadata.tags)
+	for _, tag := range tags {
+		if !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return Metadata{values: metadata.values, tags: merged}
+}
+
+// GetMetadata returns the value attached to a descriptor with the given key,
+// when it has type T.
+func GetMetadata[T any](descriptor ServiceDescriptor, key string) (T, bool) {
+	value, ok := descriptor.Metadata().Get(key)
+	typed, isT := value.(T)
+	return typed, ok && isT
+}
+
+// WithMetadata returns a descriptor like the given one, with a value attached
+// with the given key, replacing any value attached with the same key.
+func WithMetadata(descriptor ServiceDescriptor, key string, value any) ServiceDescriptor {
+	return &metadataDescriptor{
+		ServiceDescriptor: unwrapMetadata(descriptor),
+		metadata:          descriptor.Metadata().with(key, value),
+	}
+}
+
+// WithTags returns a descriptor like the given one, with the given tags attached.
+func WithTags(descriptor ServiceDescriptor, tags ...string) ServiceDescriptor {
+	return &metadataDescriptor{
+		ServiceDescriptor: unwrapMetadata(descriptor),
+		metadata:          descriptor.Metadata().withTags(tags...),
+	}
+}
+
+// HasTag returns a predicate for FindDescriptors, matching the descriptors
+// with the given tag.
+func HasTag(tag string) func(ServiceDescriptor) bool {
+	return func(descriptor ServiceDescriptor) bool {
+		return descriptor.Metadata().HasTag(tag)
+	}
+}
+
+// metadataDescriptor attaches metadata to a descriptor.
+type metadataDescriptor struct {
+	ServiceDescriptor
+	metadata Metadata
+}
+
+// Metadata implements ServiceDescriptor
+func (desc *metadataDescriptor) Metadata() Metadata {
+	return desc.metadata
+}
+
+func unwrapMetadata(descriptor ServiceDescriptor) ServiceDescriptor {
+	if wrapped, ok := descriptor.(*metadataDescriptor); ok {
+		return wrapped.ServiceDescriptor
+	}
+	return descriptor
+}
+
+// Meta is a service of type T along with the metadata of its descriptor.
+// Require Meta[T] or []Meta[T] instead of T or []T to get the metadata.
+type Meta[T any] struct {
+	Value    T
+	Metadata Metadata
+}
+
+// metaService is implemented by every Meta type.
+type metaService interface {
+	metaServiceType() reflect.Type
+}
+
+func (Meta[T]) metaServiceType() reflect.Type {
+	return typeOf[T]()
+}
+
+var typeOfMetaService = typeOf[metaService]()
+
+// unwrapMeta returns T for Meta[T].
+func unwrapMeta(serviceType reflect.Type) (reflect.Type, bool) {
+	if serviceType.Kind() != reflect.Struct || !serviceType.Implements(typeOfMetaService) {
+		return serviceType, false
+	}
+	return reflect.Zero(serviceType).Interface().(metaService).metaServiceType(), true
+}
+
+// unwrapMetaRequirement returns the requirement on T or []T behind a
+// requirement on Meta[T] or []Meta[T].
+func unwrapMetaRequirement(requirement reflect.Type) reflect.Type {
+	if requirement.Kind() == reflect.Slice {
+		if serviceType, ok := unwrapMeta(requirement.Elem()); ok {
+			return reflect.SliceOf(serviceType)
+		}
+		return requirement
+	}
+	serviceType, _ := unwrapMeta(requirement)
+	return serviceType
+}
+
+// newMeta wraps an instance into a value of the given Meta type.
+func newMeta(metaType reflect.Type, instance any, descriptor ServiceDescriptor) any {
+	meta := reflect.New(metaType).Elem()
+	if instance != nil {
+		meta.Field(0).Set(reflect.ValueOf(instance))
+	}
+	meta.Field(1).Set(reflect.ValueOf(descriptor.Metadata()))
+	return meta.Interface()
+}
+
+// taggedServiceResolver is implemented by providers able to resolve the
+// services with a tag without instantiating the others.
+type taggedServiceResolver interface {
+	getServicesWithTag(serviceType reflect.Type, tag string) (any, error)
+}
+
+// GetServicesWithTag resolves every service of type T registered with the
+// given tag, in registration order.
+func GetServicesWithTag[T any](provider ServiceProvider, tag string) ([]T, error) {
+	if resolver, ok := provider.(taggedServiceResolver); ok {
+		services, err := resolver.getServicesWithTag(typeOf[T](), tag)
+		if err != nil {
+			return nil, err
+		}
+		return services.([]T), nil
+	}
+
+	metas, err := GetService[[]Meta[T]](provider)
+	if err != nil {
+		return nil, err
+	}
+	return mapSlice(
+		filterSlice(metas, func(meta Meta[T]) bool { return meta.Metadata.HasTag(tag) }),
+		func(meta Meta[T]) T { return meta.Value }), nil
+}
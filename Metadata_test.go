@@ -0,0 +1,132 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMiddleware struct{ name string }
+
+func newTestMiddleware(name string) ServiceDescriptor {
+	return NewTransientFactory[*testMiddleware](func(ServiceProvider) (any, error) {
+		return &testMiddleware{name: name}, nil
+	})
+}
+
+type testMiddlewarePipeline struct {
+	Middlewares []Meta[*testMiddleware]
+}
+
+func TestWithMetadata(t *testing.T) {
+	descriptor := newTestMiddleware("auth")
+	assert.Empty(t, descriptor.Metadata().Keys())
+
+	withOrder := WithMetadata(descriptor, "order", 10)
+	withOwner := WithMetadata(withOrder, "owner", "platform")
+	replaced := WithMetadata(withOwner, "order", 20)
+
+	assert.Equal(t, []string{"order", "owner"}, replaced.Metadata().Keys())
+	order, ok := GetMetadata[int](replaced, "order")
+	assert.True(t, ok)
+	assert.Equal(t, 20, order)
+	// Attaching metadata does not change the original descriptors
+	order, _ = GetMetadata[int](withOwner, "order")
+	assert.Equal(t, 10, order)
+	_, ok = GetMetadata[string](replaced, "order")
+	assert.False(t, ok)
+	_, ok = replaced.Metadata().Get("version")
+	assert.False(t, ok)
+
+	assert.Equal(t, descriptor.ServiceType(), replaced.ServiceType())
+	assert.Equal(t, descriptor.Lifetime(), replaced.Lifetime())
+	assert.Equal(t, descriptor.CallSite(), replaced.CallSite())
+	assert.Same(t, descriptor.Factory(), replaced.Factory())
+}
+
+func TestWithTags(t *testing.T) {
+	descriptor := WithTags(WithTags(newTestMiddleware("auth"), "http-middleware", "security"), "security", "v2")
+
+	assert.Equal(t, []string{"http-middleware", "security", "v2"}, descriptor.Metadata().Tags())
+	assert.True(t, descriptor.Metadata().HasTag("v2"))
+	assert.False(t, descriptor.Metadata().HasTag("grpc"))
+
+	// Tags and values combine with Eager
+	eager := Eager(WithTags(NewSingletonFactory[*testWarmC](func(ServiceProvider) (any, error) {
+		return &testWarmC{}, nil
+	}), "cache"))
+	assert.True(t, IsEager(eager))
+	assert.True(t, eager.Metadata().HasTag("cache"))
+}
+
+func TestServiceCollection_FindDescriptorsByTag(t *testing.T) {
+	auth := WithTags(newTestMiddleware("auth"), "http-middleware")
+	logging := newTestMiddleware("logging")
+	cors := WithTags(newTestMiddleware("cors"), "http-middleware")
+	services := NewServiceCollection().AddRange(auth, logging, cors)
+
+	assert.Equal(t, []ServiceDescriptor{auth, cors}, services.FindDescriptors(HasTag("http-middleware")))
+}
+
+func TestGetServicesWithTag(t *testing.T) {
+	var created []string
+	newMiddleware := func(name string) ServiceDescriptor {
+		return NewTransientFactory[*testMiddleware](func(ServiceProvider) (any, error) {
+			created = append(created, name)
+			return &testMiddleware{name: name}, nil
+		})
+	}
+	container := newTestContainer(t,
+		WithTags(newMiddleware("auth"), "http-middleware"),
+		newMiddleware("logging"),
+		WithTags(newMiddleware("cors"), "http-middleware"))
+	defer container.Dispose()
+
+	middlewares, err := GetServicesWithTag[*testMiddleware](container.Provider(), "http-middleware")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"auth", "cors"}, mapSlice(middlewares, func(m *testMiddleware) string { return m.name }))
+	// Services without the tag are not instantiated
+	assert.Equal(t, []string{"auth", "cors"}, created)
+
+	middlewares, err = GetServicesWithTag[*testMiddleware](container.Provider(), "grpc")
+	assert.NoError(t, err)
+	assert.Empty(t, middlewares)
+}
+
+func TestMeta_Injection(t *testing.T) {
+	pipeline, err := NewSingletonStructPtr[testMiddlewarePipeline]()
+	assert.NoError(t, err)
+	container := newTestContainer(t,
+		WithMetadata(newTestMiddleware("auth"), "order", 2),
+		WithMetadata(newTestMiddleware("cors"), "order", 1),
+		pipeline)
+	defer container.Dispose()
+
+	resolved, err := GetService[*testMiddlewarePipeline](container.Provider())
+	assert.NoError(t, err)
+	assert.Len(t, resolved.Middlewares, 2)
+	assert.Equal(t, "auth", resolved.Middlewares[0].Value.name)
+	order, _ := resolved.Middlewares[0].Metadata.Get("order")
+	assert.Equal(t, 2, order)
+	assert.Equal(t, "cors", resolved.Middlewares[1].Value.name)
+
+	single, err := GetService[Meta[*testMiddleware]](container.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, "cors", single.Value.name)
+	order, _ = single.Metadata.Get("order")
+	assert.Equal(t, 1, order)
+}
+
+func TestMeta_Validation(t *testing.T) {
+	scoped := NewScopedFactory[*testMiddleware](func(ServiceProvider) (any, error) {
+		return &testMiddleware{}, nil
+	})
+	pipeline, err := NewSingletonStructPtr[testMiddlewarePipeline]()
+	assert.NoError(t, err)
+
+	_, err = NewServiceCollection().AddRange(scoped, pipeline).Build()
+	assert.ErrorContains(t, err, "=(invalid)=>")
+
+	_, err = GetService[Meta[*testMiddleware]](newTestContainer(t).Provider())
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+}
@@ -0,0 +1,49 @@
+package di
+
+import (
+	"reflect"
+	"time"
+)
+
+// MetricsCollector receives measurements of container activity. Implement it
+// to adapt container metrics to a metrics system, or use ExpvarMetrics.
+type MetricsCollector interface {
+	// ServiceResolved is called every time a descriptor is resolved. Cached
+	// tells whether the instance came from the container cache.
+	ServiceResolved(serviceType reflect.Type, cached bool)
+	// FactoryInvoked is called after a service factory has been invoked.
+	FactoryInvoked(serviceType reflect.Type, duration time.Duration, err error)
+	// ScopeCreated is called when a container or scope is created.
+	ScopeCreated()
+	// ScopeDisposed is called when a container or scope is disposed.
+	ScopeDisposed()
+	// DisposeFailed is called when disposing a service instance fails.
+	DisposeFailed(serviceType reflect.Type)
+}
+
+// NewMetricsHooks returns container hooks reporting container activity to the given collector.
+func NewMetricsHooks(collector MetricsCollector) ContainerHooks {
+	return ContainerHooks{
+		OnResolved: func(event ResolvedEvent) {
+			collector.ServiceResolved(event.Descriptor.ServiceType(), event.Cached)
+		},
+		OnInstantiated: func(event InstantiatedEvent) {
+			collector.FactoryInvoked(event.Descriptor.ServiceType(), event.Duration, event.Err)
+		},
+		OnScopeCreated: func(ScopeEvent) {
+			collector.ScopeCreated()
+		},
+		OnScopeDisposed: func(ScopeEvent) {
+			collector.ScopeDisposed()
+		},
+		OnDisposeFailed: func(event DisposeFailedEvent) {
+			collector.DisposeFailed(event.Descriptor.ServiceType())
+		},
+	}
+}
+
+// AddMetrics registers hooks reporting the activity of containers built from
+// the given collection to the given collector.
+func AddMetrics(services ServiceCollection, collector MetricsCollector) ServiceCollection {
+	return services.AddHooks(NewMetricsHooks(collector))
+}
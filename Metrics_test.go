@@ -0,0 +1,60 @@
+package di
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetricsCollector struct {
+	resolved        []bool
+	invoked         []reflect.Type
+	liveScopes      int
+	disposeFailures []reflect.Type
+}
+
+func (collector *testMetricsCollector) ServiceResolved(serviceType reflect.Type, cached bool) {
+	collector.resolved = append(collector.resolved, cached)
+}
+
+func (collector *testMetricsCollector) FactoryInvoked(serviceType reflect.Type, duration time.Duration, err error) {
+	collector.invoked = append(collector.invoked, serviceType)
+}
+
+func (collector *testMetricsCollector) ScopeCreated() {
+	collector.liveScopes++
+}
+
+func (collector *testMetricsCollector) ScopeDisposed() {
+	collector.liveScopes--
+}
+
+func (collector *testMetricsCollector) DisposeFailed(serviceType reflect.Type) {
+	collector.disposeFailures = append(collector.disposeFailures, serviceType)
+}
+
+func TestAddMetrics(t *testing.T) {
+	collector := &testMetricsCollector{}
+	descriptor, _ := NewScopedStruct[testServiceInterface, testServiceStruct]()
+	failing, _ := NewInstance(&testPanicDisposable{})
+	services := NewServiceCollection().AddRange(descriptor, failing)
+	AddMetrics(services, collector)
+
+	root, _ := services.Build()
+	scope, _ := root.CreateScope()
+	assert.Equal(t, 2, collector.liveScopes)
+
+	_, _ = scope.Provider().GetService(typeOfTestServiceInterface)
+	_, _ = scope.Provider().GetService(typeOfTestServiceInterface)
+	_, _ = scope.Provider().GetService(typeOf[*testPanicDisposable]())
+	assert.Equal(t, []bool{false, true, false}, collector.resolved)
+	assert.Equal(t, []reflect.Type{typeOfTestServiceInterface, typeOf[*testPanicDisposable]()}, collector.invoked)
+
+	scope.Dispose()
+	assert.Equal(t, 1, collector.liveScopes)
+	root.Dispose()
+	assert.Equal(t, 0, collector.liveScopes)
+	assert.Equal(t, []reflect.Type{typeOf[*testPanicDisposable]()}, collector.disposeFailures)
+}
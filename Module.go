@@ -0,0 +1,184 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMissingModuleDependency  = errors.New("missing module dependency")
+	ErrCircularModuleDependency = errors.New("circular module dependency")
+)
+
+// Module is a named, reusable group of service registrations.
+type Module interface {
+	// Name identifies the module. A module is installed once per name.
+	Name() string
+	// Dependencies are the names of the modules to install before this one.
+	Dependencies() []string
+	// Configure adds the module's services to the collection.
+	Configure(services ServiceCollection) error
+}
+
+// NewModule creates a new module from the given configure function.
+// parameters:
+// 	name - the module name
+// 	dependencies - the names of the modules to install before this one
+// 	configure - the function adding the module's services
+// returns:
+// 	the new module
+func NewModule(
+	name string,
+	dependencies []string,
+	configure func(services ServiceCollection) error) Module {
+	return &module{
+		name:         name,
+		dependencies: cloneSlice(dependencies),
+		configure:    configure,
+	}
+}
+
+// module is a Module implementation backed by a configure function
+type module struct {
+	name         string
+	dependencies []string
+	configure    func(services ServiceCollection) error
+}
+
+// Name implements Module
+func (mod *module) Name() string {
+	return mod.name
+}
+
+// Dependencies implements Module
+func (mod *module) Dependencies() []string {
+	return cloneSlice(mod.dependencies)
+}
+
+// Configure implements Module
+func (mod *module) Configure(services ServiceCollection) error {
+	return mod.configure(services)
+}
+
+// ModuleError is returned by Build when modules could not be installed.
+type ModuleError struct {
+	Errors []error
+}
+
+var _ error = (*ModuleError)(nil)
+
+func (err *ModuleError) Error() string {
+	var sb strings.Builder
+	for i, e := range err.Errors {
+		sb.WriteString(e.Error())
+		if i < len(err.Errors)-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func (err *ModuleError) Unwrap() []error {
+	return err.Errors
+}
+
+// moduleInstaller installs modules in dependency order, each one exactly once.
+// Modules are installed as soon as all their dependencies are installed.
+type moduleInstaller struct {
+	installed  []string
+	pending    []Module
+	errors     []error
+	installing bool
+}
+
+func (installer *moduleInstaller) isKnown(name string) bool {
+	return installer.isInstalled(name) ||
+		findSlice(installer.pending, func(mod Module) bool { return mod.Name() == name }) != nil
+}
+
+func (installer *moduleInstaller) isInstalled(name string) bool {
+	return findSlice(installer.installed, func(installed string) bool { return installed == name }) != nil
+}
+
+func (installer *moduleInstaller) add(services ServiceCollection, mod Module) {
+	if installer.isKnown(mod.Name()) {
+		return
+	}
+	installer.pending = append(installer.pending, mod)
+	installer.install(services)
+}
+
+// install installs every pending module whose dependencies are installed.
+// Modules added while configuring another module are picked up by the outer call.
+func (installer *moduleInstaller) install(services ServiceCollection) {
+	if installer.installing {
+		return
+	}
+	installer.installing = true
+	defer func() { installer.installing = false }()
+
+	for {
+		index := -1
+		for i, mod := range installer.pending {
+			if allSlice(mod.Dependencies(), installer.isInstalled) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return
+		}
+
+		mod := installer.pending[index]
+		installer.pending = append(installer.pending[:index:index], installer.pending[index+1:]...)
+
+		if err := mod.Configure(services); err != nil {
+			installer.errors = append(installer.errors, fmt.Errorf("module %s: %w", mod.Name(), err))
+		}
+		installer.installed = append(installer.installed, mod.Name())
+	}
+}
+
+// validate reports configuration errors and modules that could not be installed,
+// either because a dependency was never added or because of a dependency cycle.
+func (installer *moduleInstaller) validate() error {
+	errs := cloneSlice(installer.errors)
+
+	isMissing := func(name string) bool { return !installer.isKnown(name) }
+
+	// Modules waiting, even transitively, on a missing module are not part of a cycle
+	blocked := filterSlice(installer.pending, func(mod Module) bool {
+		return anySlice(mod.Dependencies(), isMissing)
+	})
+	for changed := true; changed; {
+		changed = false
+		for _, mod := range installer.pending {
+			if findSlice(blocked, func(other Module) bool { return other.Name() == mod.Name() }) != nil {
+				continue
+			}
+			if anySlice(mod.Dependencies(), func(name string) bool {
+				return findSlice(blocked, func(other Module) bool { return other.Name() == name }) != nil
+			}) {
+				blocked = append(blocked, mod)
+				changed = true
+			}
+		}
+	}
+
+	for _, mod := range installer.pending {
+		for _, dependency := range mod.Dependencies() {
+			if isMissing(dependency) {
+				errs = append(errs, fmt.Errorf("module %s =(%w)=> %s", mod.Name(), ErrMissingModuleDependency, dependency))
+			}
+		}
+		if findSlice(blocked, func(other Module) bool { return other.Name() == mod.Name() }) == nil {
+			errs = append(errs, fmt.Errorf("module %s: %w", mod.Name(), ErrCircularModuleDependency))
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ModuleError{Errors: errs}
+	}
+	return nil
+}
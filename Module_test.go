@@ -0,0 +1,110 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestModule(name string, installs *[]string, dependencies ...string) Module {
+	return NewModule(name, dependencies, func(services ServiceCollection) error {
+		*installs = append(*installs, name)
+		return nil
+	})
+}
+
+func TestNewModule(t *testing.T) {
+	services := NewServiceCollection()
+	descriptor, _ := NewInstance(&testStructWithFields{})
+	dependencies := []string{"other"}
+	module := NewModule("module", dependencies, func(services ServiceCollection) error {
+		services.Add(descriptor)
+		return nil
+	})
+	dependencies[0] = "changed"
+
+	assert.Equal(t, "module", module.Name())
+	assert.Equal(t, []string{"other"}, module.Dependencies())
+	assert.NoError(t, module.Configure(services))
+	assert.Equal(t, []ServiceDescriptor{descriptor}, services.ListDescriptors())
+}
+
+func TestServiceCollection_AddModule_Once(t *testing.T) {
+	var installs []string
+	services := NewServiceCollection()
+
+	services.AddModule(newTestModule("a", &installs))
+	services.AddModule(newTestModule("a", &installs))
+
+	assert.Equal(t, []string{"a"}, installs)
+	_, err := services.Build()
+	assert.NoError(t, err)
+}
+
+func TestServiceCollection_AddModule_DependencyOrder(t *testing.T) {
+	var installs []string
+	services := NewServiceCollection()
+
+	services.AddModule(newTestModule("c", &installs, "a", "b"))
+	services.AddModule(newTestModule("b", &installs, "a"))
+	assert.Empty(t, installs)
+	services.AddModule(newTestModule("a", &installs))
+
+	assert.Equal(t, []string{"a", "b", "c"}, installs)
+	_, err := services.Build()
+	assert.NoError(t, err)
+}
+
+func TestServiceCollection_AddModule_Nested(t *testing.T) {
+	var installs []string
+	services := NewServiceCollection()
+
+	services.AddModule(NewModule("app", nil, func(services ServiceCollection) error {
+		services.AddModule(newTestModule("lib", &installs, "app"))
+		services.AddModule(newTestModule("base", &installs))
+		installs = append(installs, "app")
+		return nil
+	}))
+
+	assert.Equal(t, []string{"app", "lib", "base"}, installs)
+}
+
+func TestServiceCollection_AddModule_MissingDependency(t *testing.T) {
+	var installs []string
+	services := NewServiceCollection()
+
+	services.AddModule(newTestModule("a", &installs, "missing"))
+	services.AddModule(newTestModule("b", &installs, "a"))
+
+	assert.Empty(t, installs)
+	container, err := services.Build()
+	assert.Nil(t, container)
+	assert.ErrorIs(t, err, ErrMissingModuleDependency)
+	assert.NotErrorIs(t, err, ErrCircularModuleDependency)
+	assert.Equal(t, "module a =(missing module dependency)=> missing", err.Error())
+}
+
+func TestServiceCollection_AddModule_CircularDependency(t *testing.T) {
+	var installs []string
+	services := NewServiceCollection()
+
+	services.AddModule(newTestModule("a", &installs, "b"))
+	services.AddModule(newTestModule("b", &installs, "a"))
+
+	assert.Empty(t, installs)
+	_, err := services.Build()
+	assert.ErrorIs(t, err, ErrCircularModuleDependency)
+	assert.Equal(t, "module a: circular module dependency\nmodule b: circular module dependency", err.Error())
+}
+
+func TestServiceCollection_AddModule_ConfigureError(t *testing.T) {
+	services := NewServiceCollection()
+
+	services.AddModule(NewModule("failing", nil, func(ServiceCollection) error {
+		return errTestFactory
+	}))
+
+	_, err := services.Build()
+	assert.ErrorIs(t, err, errTestFactory)
+	assert.Equal(t, "module failing: test factory", err.Error())
+}
@@ -0,0 +1,172 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrRequiredOption = errors.New("required option is missing")
+
+// DefaultOptionsName is the name of the options configured by Configure.
+const DefaultOptionsName = ""
+
+// Options gives access to configuration values of type T, bound and validated
+// once when the options service is created.
+type Options[T any] interface {
+	// Value returns the options configured without a name.
+	Value() T
+	// Get returns the options configured with the given name, or the zero
+	// value of T when no options were configured with that name.
+	Get(name string) T
+}
+
+// options is the Options implementation
+type options[T any] struct {
+	values map[string]T
+}
+
+var _ Options[any] = (*options[any])(nil)
+
+// Value implements Options
+func (opts *options[T]) Value() T {
+	return opts.values[DefaultOptionsName]
+}
+
+// Get implements Options
+func (opts *options[T]) Get(name string) T {
+	return opts.values[name]
+}
+
+// optionsBinding is registered once per call to ConfigureNamed, so that all
+// the sources of an options type are injected into its Options service.
+type optionsBinding[T any] struct {
+	name    string
+	sources []OptionsSource
+}
+
+// Configure registers Options[T] as a singleton, bound from the given sources,
+// along with OptionsMonitor[T] and OptionsSnapshot[T].
+// Sources are applied in order, so later sources override earlier ones, and
+// calling Configure again for the same type appends more sources.
+// Fields tagged `options:"required"` must not be left empty, and options
+// implementing `Validate() error` are validated too. Binding and validation
+// errors make Build fail.
+func Configure[T any](services ServiceCollection, sources ...OptionsSource) ServiceCollection {
+	return ConfigureNamed[T](services, DefaultOptionsName, sources...)
+}
+
+// ConfigureNamed is like Configure, for options configured with the given
+// name, which are returned by Options[T].Get.
+func ConfigureNamed[T any](services ServiceCollection, name string, sources ...OptionsSource) ServiceCollection {
+	binding := &optionsBinding[T]{
+		name:    name,
+		sources: cloneSlice(sources),
+	}
+	services.Add(NewSingletonFactory[*optionsBinding[T]](
+		func(ServiceProvider) (any, error) {
+			return binding, nil
+		}))
+
+	if services.FindFirstDescriptorForType(typeOf[Options[T]]()) != nil {
+		return services
+	}
+
+	services.Add(NewSingletonServiceFactory[Options[T]](NewFactoryWith(
+		fmt.Sprintf("Options[%s]", typeOf[T]()),
+		[]reflect.Type{typeOf[[]*optionsBinding[T]]()},
+		func(provider ServiceProvider) (any, error) {
+			bindings, err := GetService[[]*optionsBinding[T]](provider)
+			if err != nil {
+				return nil, err
+			}
+			values, err := bindOptions(bindings)
+			if err != nil {
+				return nil, err
+			}
+			return &options[T]{values: values}, nil
+		})))
+	addOptionsMonitor[T](services)
+
+	return services.AddValidator(func(provider ServiceProvider) error {
+		_, err := GetService[Options[T]](provider)
+		return err
+	})
+}
+
+// bindOptions binds and validates the options of every name.
+func bindOptions[T any](bindings []*optionsBinding[T]) (map[string]T, error) {
+	values := map[string]T{}
+	var names []string
+	var errs []error
+
+	for _, binding := range bindings {
+		value, ok := values[binding.name]
+		if !ok {
+			names = append(names, binding.name)
+		}
+		for _, source := range binding.sources {
+			if err := source.Bind(&value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", optionsName[T](binding.name), err))
+			}
+		}
+		values[binding.name] = value
+	}
+
+	for _, name := range names {
+		value := values[name]
+		for _, err := range validateOptions(&value) {
+			errs = append(errs, fmt.Errorf("%s: %w", optionsName[T](name), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return values, nil
+}
+
+func optionsName[T any](name string) string {
+	if name == DefaultOptionsName {
+		return fmt.Sprintf("options %s", typeOf[T]())
+	}
+	return fmt.Sprintf("options %s %q", typeOf[T](), name)
+}
+
+// validateOptions checks required fields, then the Validate method of the options.
+func validateOptions(target any) []error {
+	errs := validateRequiredOptions("", reflect.ValueOf(target).Elem())
+	if validator, ok := target.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func validateRequiredOptions(path string, value reflect.Value) []error {
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []error
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		fieldValue := value.Field(i)
+		tags := strings.Split(field.Tag.Get("options"), ",")
+		if anySlice(tags, func(tag string) bool { return tag == "required" }) && fieldValue.IsZero() {
+			errs = append(errs, fmt.Errorf("%s: %w", fieldPath, ErrRequiredOption))
+		}
+		errs = append(errs, validateRequiredOptions(fieldPath, fieldValue)...)
+	}
+	return errs
+}
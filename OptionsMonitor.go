@@ -0,0 +1,255 @@
+package di
+
+import (
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// DefaultOptionsWatchInterval is the interval at which an OptionsMonitor
+// checks its configuration files for changes, unless set with ConfigureWatch.
+const DefaultOptionsWatchInterval = time.Second
+
+// OptionsMonitor gives access to configuration values of type T, bound again
+// every time one of their JSON files changes. A change leading to binding or
+// validation errors is ignored, and the previous values are kept.
+type OptionsMonitor[T any] interface {
+	// Current returns the current options configured without a name.
+	Current() T
+	// Get returns the current options configured with the given name.
+	Get(name string) T
+	// OnChange registers a listener called with the options configured
+	// without a name every time the options are bound again.
+	// Dispose the returned value to unregister the listener.
+	OnChange(listener func(T)) Disposable
+}
+
+// OptionsSnapshot gives access to configuration values of type T, which stay
+// the same for the lifetime of a scope. The values are taken from the
+// OptionsMonitor the first time the snapshot is requested in the scope.
+type OptionsSnapshot[T any] interface {
+	Options[T]
+}
+
+// ConfigureWatch sets the interval at which the OptionsMonitor of T checks its
+// configuration files for changes.
+func ConfigureWatch[T any](services ServiceCollection, interval time.Duration) ServiceCollection {
+	watch := &optionsWatch[T]{interval: interval}
+	return services.Add(NewSingletonFactory[*optionsWatch[T]](
+		func(ServiceProvider) (any, error) {
+			return watch, nil
+		}))
+}
+
+// optionsWatch holds the watch interval of an options type.
+type optionsWatch[T any] struct {
+	interval time.Duration
+}
+
+// addOptionsMonitor registers OptionsMonitor[T] and OptionsSnapshot[T].
+func addOptionsMonitor[T any](services ServiceCollection) {
+	services.Add(NewSingletonServiceFactory[OptionsMonitor[T]](NewFactoryWith(
+		"OptionsMonitor["+typeOf[T]().String()+"]",
+		[]reflect.Type{typeOf[[]*optionsBinding[T]](), typeOf[[]*optionsWatch[T]]()},
+		func(provider ServiceProvider) (any, error) {
+			bindings, err := GetService[[]*optionsBinding[T]](provider)
+			if err != nil {
+				return nil, err
+			}
+			watches, err := GetService[[]*optionsWatch[T]](provider)
+			if err != nil {
+				return nil, err
+			}
+			interval := DefaultOptionsWatchInterval
+			if len(watches) > 0 {
+				interval = watches[len(watches)-1].interval
+			}
+			monitor, err := newOptionsMonitor(bindings, interval)
+			if err != nil {
+				return nil, err
+			}
+			return monitor, nil
+		})))
+
+	services.Add(NewScopedServiceFactory[OptionsSnapshot[T]](NewFactoryWith(
+		"OptionsSnapshot["+typeOf[T]().String()+"]",
+		[]reflect.Type{typeOf[OptionsMonitor[T]]()},
+		func(provider ServiceProvider) (any, error) {
+			monitor, err := GetService[OptionsMonitor[T]](provider)
+			if err != nil {
+				return nil, err
+			}
+			if monitor, ok := monitor.(*optionsMonitor[T]); ok {
+				return monitor.snapshot(), nil
+			}
+			return &options[T]{values: map[string]T{DefaultOptionsName: monitor.Current()}}, nil
+		})))
+}
+
+// optionsMonitor is the OptionsMonitor implementation. It polls the files of
+// its JSON file sources until disposed.
+type optionsMonitor[T any] struct {
+	mutex     sync.RWMutex
+	bindings  []*optionsBinding[T]
+	values    map[string]T
+	listeners []*func(T)
+	files     map[string]fileState
+	stopOnce  sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+var _ OptionsMonitor[any] = (*optionsMonitor[any])(nil)
+var _ Disposable = (*optionsMonitor[any])(nil)
+
+// fileState is what is compared to detect a file change.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// watchedFileSource is implemented by sources backed by a file.
+type watchedFileSource interface {
+	watchedFile() string
+}
+
+func newOptionsMonitor[T any](bindings []*optionsBinding[T], interval time.Duration) (*optionsMonitor[T], error) {
+	monitor := &optionsMonitor[T]{
+		bindings: bindings,
+		files:    map[string]fileState{},
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	for _, binding := range bindings {
+		for _, source := range binding.sources {
+			if watched, ok := source.(watchedFileSource); ok {
+				path := watched.watchedFile()
+				monitor.files[path] = statFile(path)
+			}
+		}
+	}
+
+	values, err := bindOptions(bindings)
+	if err != nil {
+		return nil, err
+	}
+	monitor.values = values
+
+	if len(monitor.files) == 0 || interval <= 0 {
+		close(monitor.done)
+		return monitor, nil
+	}
+
+	go monitor.watch(interval)
+	return monitor, nil
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{
+		exists:  true,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	}
+}
+
+func (monitor *optionsMonitor[T]) watch(interval time.Duration) {
+	defer close(monitor.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-monitor.stop:
+			return
+		case <-ticker.C:
+			if monitor.filesChanged() {
+				// On errors, the previous values are kept
+				_ = monitor.reload()
+			}
+		}
+	}
+}
+
+func (monitor *optionsMonitor[T]) filesChanged() bool {
+	changed := false
+	for path, previous := range monitor.files {
+		current := statFile(path)
+		if current.exists != previous.exists ||
+			current.size != previous.size ||
+			!current.modTime.Equal(previous.modTime) {
+			monitor.files[path] = current
+			changed = true
+		}
+	}
+	return changed
+}
+
+// reload binds the options again, then notifies the listeners.
+func (monitor *optionsMonitor[T]) reload() error {
+	values, err := bindOptions(monitor.bindings)
+	if err != nil {
+		return err
+	}
+
+	monitor.mutex.Lock()
+	monitor.values = values
+	listeners := cloneSlice(monitor.listeners)
+	monitor.mutex.Unlock()
+
+	current := values[DefaultOptionsName]
+	for _, listener := range listeners {
+		(*listener)(current)
+	}
+	return nil
+}
+
+// Current implements OptionsMonitor
+func (monitor *optionsMonitor[T]) Current() T {
+	return monitor.Get(DefaultOptionsName)
+}
+
+// Get implements OptionsMonitor
+func (monitor *optionsMonitor[T]) Get(name string) T {
+	monitor.mutex.RLock()
+	defer monitor.mutex.RUnlock()
+	return monitor.values[name]
+}
+
+// OnChange implements OptionsMonitor
+func (monitor *optionsMonitor[T]) OnChange(listener func(T)) Disposable {
+	registered := &listener
+
+	monitor.mutex.Lock()
+	monitor.listeners = append(monitor.listeners, registered)
+	monitor.mutex.Unlock()
+
+	return NewDisposable(func() {
+		monitor.mutex.Lock()
+		defer monitor.mutex.Unlock()
+		monitor.listeners = filterSlice(monitor.listeners, func(other *func(T)) bool {
+			return other != registered
+		})
+	})
+}
+
+// Dispose implements Disposable, to stop watching files.
+func (monitor *optionsMonitor[T]) Dispose() {
+	monitor.stopOnce.Do(func() { close(monitor.stop) })
+	<-monitor.done
+}
+
+// snapshot returns options holding the current values.
+// Values are never mutated once bound, so they can be shared.
+func (monitor *optionsMonitor[T]) snapshot() *options[T] {
+	monitor.mutex.RLock()
+	defer monitor.mutex.RUnlock()
+	return &options[T]{values: monitor.values}
+}
@@ -0,0 +1,108 @@
+package di
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestOptionsContainer(t *testing.T, path string, interval time.Duration) ServiceContainer {
+	services := NewServiceCollection()
+	Configure[testOptions](services, DefaultsSource(testOptions{Name: "default"}), JSONFileSource(path))
+	ConfigureWatch[testOptions](services, interval)
+	container, err := services.Build()
+	assert.NoError(t, err)
+	t.Cleanup(container.Dispose)
+	return container
+}
+
+func TestOptionsMonitor_WatchesFile(t *testing.T) {
+	path := writeTestFile(t, `{"Port": 80}`)
+	container := newTestOptionsContainer(t, path, 5*time.Millisecond)
+
+	monitor, err := GetService[OptionsMonitor[testOptions]](container.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(80), monitor.Current().Port)
+
+	var mutex sync.Mutex
+	var changes []uint16
+	subscription := monitor.OnChange(func(options testOptions) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		changes = append(changes, options.Port)
+	})
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"Port": 8080}`), 0o600))
+	assert.Eventually(t, func() bool {
+		return monitor.Current().Port == 8080
+	}, time.Second, time.Millisecond)
+
+	mutex.Lock()
+	assert.Equal(t, []uint16{8080}, changes)
+	mutex.Unlock()
+
+	subscription.Dispose()
+	assert.NoError(t, os.WriteFile(path, []byte(`{"Port": 90}`), 0o600))
+	assert.Eventually(t, func() bool {
+		return monitor.Current().Port == 90
+	}, time.Second, time.Millisecond)
+
+	mutex.Lock()
+	assert.Equal(t, []uint16{8080}, changes)
+	mutex.Unlock()
+}
+
+func TestOptionsMonitor_KeepsValuesOnInvalidChange(t *testing.T) {
+	path := writeTestFile(t, `{"Port": 80}`)
+	container := newTestOptionsContainer(t, path, 0)
+	monitor, _ := GetService[OptionsMonitor[testOptions]](container.Provider())
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"Name": ""}`), 0o600))
+	err := monitor.(*optionsMonitor[testOptions]).reload()
+	assert.ErrorIs(t, err, ErrRequiredOption)
+	assert.Equal(t, testOptions{Name: "default", Port: 80}, monitor.Current())
+}
+
+func TestOptionsSnapshot_StableForScope(t *testing.T) {
+	path := writeTestFile(t, `{"Port": 80}`)
+	container := newTestOptionsContainer(t, path, 0)
+	monitor, _ := GetService[OptionsMonitor[testOptions]](container.Provider())
+
+	_, err := GetService[OptionsSnapshot[testOptions]](container.Provider())
+	assert.ErrorIs(t, err, ErrScopedServiceFromRoot)
+
+	scope1, _ := container.CreateScope()
+	snapshot1, err := GetService[OptionsSnapshot[testOptions]](scope1.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(80), snapshot1.Value().Port)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"Port": 8080}`), 0o600))
+	assert.NoError(t, monitor.(*optionsMonitor[testOptions]).reload())
+
+	snapshot1, _ = GetService[OptionsSnapshot[testOptions]](scope1.Provider())
+	assert.Equal(t, uint16(80), snapshot1.Value().Port)
+
+	scope2, _ := container.CreateScope()
+	snapshot2, _ := GetService[OptionsSnapshot[testOptions]](scope2.Provider())
+	assert.Equal(t, uint16(8080), snapshot2.Value().Port)
+
+	options, _ := GetService[Options[testOptions]](container.Provider())
+	assert.Equal(t, uint16(80), options.Value().Port)
+}
+
+func TestOptionsMonitor_StopsWatchingOnDispose(t *testing.T) {
+	path := writeTestFile(t, `{"Port": 80}`)
+	container := newTestOptionsContainer(t, path, time.Millisecond)
+	monitor, _ := GetService[OptionsMonitor[testOptions]](container.Provider())
+
+	container.Dispose()
+
+	select {
+	case <-monitor.(*optionsMonitor[testOptions]).done:
+	default:
+		assert.Fail(t, "monitor is still watching")
+	}
+}
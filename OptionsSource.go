@@ -0,0 +1,222 @@
+package di
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrUnsupportedOptionType = errors.New("unsupported option type")
+
+// OptionsSource binds configuration values into an options struct.
+type OptionsSource interface {
+	// Bind sets the values known to the source into target, a pointer to a struct.
+	Bind(target any) error
+}
+
+// OptionsSourceFunc is an OptionsSource backed by a bind function.
+type OptionsSourceFunc func(target any) error
+
+// Bind implements OptionsSource
+func (bind OptionsSourceFunc) Bind(target any) error {
+	return bind(target)
+}
+
+// DefaultsSource returns a source setting the options to the given in-code defaults.
+func DefaultsSource[T any](defaults T) OptionsSource {
+	return OptionsSourceFunc(func(target any) error {
+		typed, ok := target.(*T)
+		if !ok {
+			return ErrUnsupportedOptionType
+		}
+		*typed = defaults
+		return nil
+	})
+}
+
+// JSONFileSource returns a source binding the fields found in a JSON file.
+// Fields missing from the file keep their current value.
+func JSONFileSource(path string) OptionsSource {
+	return &jsonFileSource{path: path}
+}
+
+// OptionalJSONFileSource returns a source like JSONFileSource, which does
+// nothing when the file does not exist.
+func OptionalJSONFileSource(path string) OptionsSource {
+	return &jsonFileSource{path: path, optional: true}
+}
+
+type jsonFileSource struct {
+	path     string
+	optional bool
+}
+
+var _ watchedFileSource = (*jsonFileSource)(nil)
+
+func (source *jsonFileSource) watchedFile() string {
+	return source.path
+}
+
+// Bind implements OptionsSource
+func (source *jsonFileSource) Bind(target any) error {
+	data, err := os.ReadFile(source.path)
+	if err != nil {
+		if source.optional && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("%s: %w", source.path, err)
+	}
+	return nil
+}
+
+// EnvSource returns a source binding fields from environment variables.
+// A field is bound from the variable named after its `env` tag, or after its
+// name in upper snake case, preceded by the prefix and an underscore. Nested
+// structs use the variable name of their field as prefix. For instance, with
+// prefix APP, the field Database.MaxConnections is bound from APP_DATABASE_MAX_CONNECTIONS.
+// Slices are bound from comma separated values.
+func EnvSource(prefix string) OptionsSource {
+	return &envSource{prefix: strings.TrimSuffix(prefix, "_"), lookup: os.LookupEnv}
+}
+
+type envSource struct {
+	prefix string
+	lookup func(key string) (string, bool)
+}
+
+// Bind implements OptionsSource
+func (source *envSource) Bind(target any) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return ErrUnsupportedOptionType
+	}
+	return source.bindStruct(source.prefix, value.Elem())
+}
+
+var typeOfTextUnmarshaler = typeOf[encoding.TextUnmarshaler]()
+var typeOfDuration = typeOf[time.Duration]()
+
+func (source *envSource) bindStruct(prefix string, value reflect.Value) error {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("env")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = toEnvName(field.Name)
+		}
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		fieldValue := value.Field(i)
+		isText := reflect.PointerTo(field.Type).Implements(typeOfTextUnmarshaler)
+		if field.Type.Kind() == reflect.Struct && !isText {
+			if err := source.bindStruct(name, fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, ok := source.lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setOptionValue(fieldValue, raw); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setOptionValue parses raw into the given addressable value.
+func setOptionValue(value reflect.Value, raw string) error {
+	if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(raw))
+	}
+
+	if value.Type() == typeOfDuration {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(parsed)
+	case reflect.Slice:
+		items := []string{}
+		if raw != "" {
+			items = strings.Split(raw, ",")
+		}
+		slice := reflect.MakeSlice(value.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setOptionValue(slice.Index(i), strings.TrimSpace(item)); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+	default:
+		return ErrUnsupportedOptionType
+	}
+	return nil
+}
+
+// toEnvName converts a Go identifier to upper snake case, e.g. HTTPPort to HTTP_PORT.
+func toEnvName(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				sb.WriteRune('_')
+			}
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
@@ -0,0 +1,119 @@
+package di
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDatabaseOptions struct {
+	Host           string
+	MaxConnections int
+}
+
+type testOptions struct {
+	Name     string `options:"required"`
+	Port     uint16
+	Debug    bool
+	Ratio    float64
+	Timeout  time.Duration
+	Tags     []string
+	Address  net.IP
+	Database testDatabaseOptions
+	Renamed  string `env:"OTHER_NAME" json:"renamed"`
+	Ignored  string `env:"-"`
+	internal string
+}
+
+func TestDefaultsSource(t *testing.T) {
+	var target testOptions
+	err := DefaultsSource(testOptions{Name: "default", Port: 80}).Bind(&target)
+	assert.NoError(t, err)
+	assert.Equal(t, testOptions{Name: "default", Port: 80}, target)
+
+	err = DefaultsSource(testOptions{}).Bind(&testDatabaseOptions{})
+	assert.Equal(t, ErrUnsupportedOptionType, err)
+}
+
+func writeTestFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "options.json")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestJSONFileSource(t *testing.T) {
+	path := writeTestFile(t, `{"Port": 8080, "Database": {"Host": "db"}, "renamed": "json"}`)
+	target := testOptions{Name: "default", Port: 80}
+
+	err := JSONFileSource(path).Bind(&target)
+	assert.NoError(t, err)
+	assert.Equal(t, testOptions{
+		Name:     "default",
+		Port:     8080,
+		Database: testDatabaseOptions{Host: "db"},
+		Renamed:  "json",
+	}, target)
+}
+
+func TestJSONFileSource_Invalid(t *testing.T) {
+	path := writeTestFile(t, `{"Port": "not a number"}`)
+	err := JSONFileSource(path).Bind(&testOptions{})
+	assert.ErrorContains(t, err, path)
+}
+
+func TestJSONFileSource_Missing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+	assert.ErrorIs(t, JSONFileSource(path).Bind(&testOptions{}), os.ErrNotExist)
+	assert.NoError(t, OptionalJSONFileSource(path).Bind(&testOptions{}))
+}
+
+func TestEnvSource(t *testing.T) {
+	t.Setenv("APP_NAME", "env")
+	t.Setenv("APP_PORT", "9090")
+	t.Setenv("APP_DEBUG", "true")
+	t.Setenv("APP_RATIO", "0.5")
+	t.Setenv("APP_TIMEOUT", "3s")
+	t.Setenv("APP_TAGS", "a, b,c")
+	t.Setenv("APP_ADDRESS", "10.0.0.1")
+	t.Setenv("APP_DATABASE_MAX_CONNECTIONS", "20")
+	t.Setenv("APP_OTHER_NAME", "renamed")
+	t.Setenv("APP_IGNORED", "ignored")
+	target := testOptions{Database: testDatabaseOptions{Host: "db"}}
+
+	err := EnvSource("APP_").Bind(&target)
+	assert.NoError(t, err)
+	assert.Equal(t, testOptions{
+		Name:     "env",
+		Port:     9090,
+		Debug:    true,
+		Ratio:    0.5,
+		Timeout:  3 * time.Second,
+		Tags:     []string{"a", "b", "c"},
+		Address:  net.ParseIP("10.0.0.1"),
+		Database: testDatabaseOptions{Host: "db", MaxConnections: 20},
+		Renamed:  "renamed",
+	}, target)
+}
+
+func TestEnvSource_InvalidValue(t *testing.T) {
+	t.Setenv("APP_PORT", "-1")
+	err := EnvSource("APP").Bind(&testOptions{})
+	assert.ErrorContains(t, err, "APP_PORT")
+}
+
+func TestEnvSource_NotAStruct(t *testing.T) {
+	var target int
+	assert.Equal(t, ErrUnsupportedOptionType, EnvSource("APP").Bind(&target))
+}
+
+func TestToEnvName(t *testing.T) {
+	assert.Equal(t, "NAME", toEnvName("Name"))
+	assert.Equal(t, "MAX_CONNECTIONS", toEnvName("MaxConnections"))
+	assert.Equal(t, "HTTP_PORT", toEnvName("HTTPPort"))
+	assert.Equal(t, "USE_HTTP", toEnvName("UseHTTP"))
+	assert.Equal(t, "V2_API", toEnvName("V2Api"))
+}
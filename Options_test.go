@@ -0,0 +1,88 @@
+package di
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (options *testDatabaseOptions) Validate() error {
+	if options.MaxConnections < 0 {
+		return errors.New("max connections must not be negative")
+	}
+	return nil
+}
+
+func TestConfigure(t *testing.T) {
+	t.Setenv("APP_PORT", "9090")
+	path := writeTestFile(t, `{"Port": 8080, "Database": {"Host": "db"}}`)
+	services := NewServiceCollection()
+
+	Configure[testOptions](services,
+		DefaultsSource(testOptions{Name: "default", Port: 80}),
+		JSONFileSource(path))
+	Configure[testOptions](services, EnvSource("APP"))
+
+	container, err := services.Build()
+	assert.NoError(t, err)
+
+	options, err := GetService[Options[testOptions]](container.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, testOptions{
+		Name:     "default",
+		Port:     9090,
+		Database: testDatabaseOptions{Host: "db"},
+	}, options.Value())
+
+	info := container.Provider().GetServiceInfo(typeOf[Options[testOptions]]())
+	assert.Equal(t, Singleton, info.Lifetime)
+	assert.True(t, info.IsInstantiated)
+}
+
+func TestConfigureNamed(t *testing.T) {
+	services := NewServiceCollection()
+
+	ConfigureNamed[testDatabaseOptions](services, "primary",
+		DefaultsSource(testDatabaseOptions{Host: "primary"}))
+	ConfigureNamed[testDatabaseOptions](services, "replica",
+		DefaultsSource(testDatabaseOptions{Host: "replica"}))
+
+	container, err := services.Build()
+	assert.NoError(t, err)
+
+	options, _ := GetService[Options[testDatabaseOptions]](container.Provider())
+	assert.Equal(t, "primary", options.Get("primary").Host)
+	assert.Equal(t, "replica", options.Get("replica").Host)
+	assert.Equal(t, testDatabaseOptions{}, options.Get("missing"))
+	assert.Equal(t, testDatabaseOptions{}, options.Value())
+}
+
+func TestConfigure_RequiredField(t *testing.T) {
+	services := NewServiceCollection()
+	Configure[testOptions](services)
+
+	container, err := services.Build()
+	assert.Nil(t, container)
+	assert.ErrorIs(t, err, ErrRequiredOption)
+	assert.ErrorContains(t, err, "options di.testOptions: Name: required option is missing")
+}
+
+func TestConfigure_ValidateMethod(t *testing.T) {
+	services := NewServiceCollection()
+	ConfigureNamed[testDatabaseOptions](services, "primary",
+		DefaultsSource(testDatabaseOptions{MaxConnections: -1}))
+
+	_, err := services.Build()
+	assert.ErrorContains(t, err,
+		"options di.testDatabaseOptions \"primary\": max connections must not be negative")
+}
+
+func TestConfigure_SourceError(t *testing.T) {
+	t.Setenv("APP_DEBUG", "maybe")
+	services := NewServiceCollection()
+	Configure[testOptions](services, DefaultsSource(testOptions{Name: "name"}), EnvSource("APP"))
+
+	_, err := services.Build()
+	assert.ErrorContains(t, err, "options di.testOptions: APP_DEBUG")
+}
@@ -0,0 +1,159 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrContradictoryOrder = errors.New("contradictory service order constraints")
+
+// Metadata keys set by the ordering functions.
+const (
+	OrderMetadataKey  = "di.order"
+	NameMetadataKey   = "di.name"
+	BeforeMetadataKey = "di.before"
+	AfterMetadataKey  = "di.after"
+)
+
+// WithOrder returns a descriptor like the given one, ordered by the given
+// number among the descriptors of its service type. Lower orders come first,
+// and descriptors without an order have order 0.
+//
+// Descriptors of a same service type are resolved as slices in order, and the
+// last one is resolved as a single service. Descriptors with the same order
+// keep their registration order.
+func WithOrder(descriptor ServiceDescriptor, order int) ServiceDescriptor {
+	return WithMetadata(descriptor, OrderMetadataKey, order)
+}
+
+// WithName returns a descriptor like the given one, named for WithBefore and
+// WithAfter. Descriptors without a name are named after their factory's
+// display name.
+func WithName(descriptor ServiceDescriptor, name string) ServiceDescriptor {
+	return WithMetadata(descriptor, NameMetadataKey, name)
+}
+
+// WithBefore returns a descriptor like the given one, ordered before the
+// descriptors of its service type with the given names. Names not registered
+// for the service type are ignored. Build fails on contradictory constraints.
+func WithBefore(descriptor ServiceDescriptor, names ...string) ServiceDescriptor {
+	before, _ := GetMetadata[[]string](descriptor, BeforeMetadataKey)
+	return WithMetadata(descriptor, BeforeMetadataKey, append(cloneSlice(before), names...))
+}
+
+// WithAfter returns a descriptor like the given one, ordered after the
+// descriptors of its service type with the given names. See WithBefore.
+func WithAfter(descriptor ServiceDescriptor, names ...string) ServiceDescriptor {
+	after, _ := GetMetadata[[]string](descriptor, AfterMetadataKey)
+	return WithMetadata(descriptor, AfterMetadataKey, append(cloneSlice(after), names...))
+}
+
+func descriptorName(descriptor ServiceDescriptor) string {
+	if name, ok := GetMetadata[string](descriptor, NameMetadataKey); ok {
+		return name
+	}
+	return descriptor.Factory().DisplayName()
+}
+
+func descriptorOrder(descriptor ServiceDescriptor) int {
+	order, _ := GetMetadata[int](descriptor, OrderMetadataKey)
+	return order
+}
+
+// sortDescriptors orders the descriptors of each service type, keeping the
+// positions of the service types in the list.
+func sortDescriptors(descriptors []ServiceDescriptor) ([]ServiceDescriptor, error) {
+	groups := map[reflect.Type][]int{}
+	var serviceTypes []reflect.Type
+	for i, descriptor := range descriptors {
+		serviceType := descriptor.ServiceType()
+		if _, ok := groups[serviceType]; !ok {
+			serviceTypes = append(serviceTypes, serviceType)
+		}
+		groups[serviceType] = append(groups[serviceType], i)
+	}
+
+	sorted := cloneSlice(descriptors)
+	var errs []error
+	for _, serviceType := range serviceTypes {
+		positions := groups[serviceType]
+		if len(positions) < 2 {
+			continue
+		}
+		group, err := sortDescriptorGroup(mapSlice(positions, func(i int) ServiceDescriptor {
+			return descriptors[i]
+		}))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for i, position := range positions {
+			sorted[position] = group[i]
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return sorted, nil
+}
+
+// sortDescriptorGroup orders the descriptors of a service type by their
+// constraints, then their order, then their registration order.
+func sortDescriptorGroup(group []ServiceDescriptor) ([]ServiceDescriptor, error) {
+	indices := map[string][]int{}
+	for i, descriptor := range group {
+		name := descriptorName(descriptor)
+		indices[name] = append(indices[name], i)
+	}
+
+	// predecessors[i] holds the descriptors which must come before i
+	predecessors := make([][]int, len(group))
+	for i, descriptor := range group {
+		before, _ := GetMetadata[[]string](descriptor, BeforeMetadataKey)
+		for _, name := range before {
+			for _, j := range indices[name] {
+				if j != i {
+					predecessors[j] = append(predecessors[j], i)
+				}
+			}
+		}
+		after, _ := GetMetadata[[]string](descriptor, AfterMetadataKey)
+		for _, name := range after {
+			for _, j := range indices[name] {
+				if j != i {
+					predecessors[i] = append(predecessors[i], j)
+				}
+			}
+		}
+	}
+
+	sorted := make([]ServiceDescriptor, 0, len(group))
+	done := make([]bool, len(group))
+	for len(sorted) < len(group) {
+		next := -1
+		for i := range group {
+			if done[i] || !allSlice(predecessors[i], func(j int) bool { return done[j] }) {
+				continue
+			}
+			if next < 0 || descriptorOrder(group[i]) < descriptorOrder(group[next]) {
+				next = i
+			}
+		}
+		if next < 0 {
+			remaining := filterSlice(rangeSlice(0, len(group)), func(i int) bool { return !done[i] })
+			return nil, fmt.Errorf(
+				"%w: %s",
+				ErrContradictoryOrder,
+				strings.Join(mapSlice(remaining, func(i int) string {
+					return fmt.Sprintf("%s %q", group[i], descriptorName(group[i]))
+				}), ", "))
+		}
+		done[next] = true
+		sorted = append(sorted, group[next])
+	}
+
+	return sorted, nil
+}
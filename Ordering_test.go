@@ -0,0 +1,119 @@
+package di
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resolveTestMiddlewareNames(t *testing.T, descriptors ...ServiceDescriptor) []string {
+	container := newTestContainer(t, descriptors...)
+	defer container.Dispose()
+
+	middlewares, err := GetService[[]*testMiddleware](container.Provider())
+	assert.NoError(t, err)
+	return mapSlice(middlewares, func(m *testMiddleware) string { return m.name })
+}
+
+func TestWithOrder(t *testing.T) {
+	names := resolveTestMiddlewareNames(t,
+		WithOrder(newTestMiddleware("logging"), 10),
+		newTestMiddleware("auth"),
+		WithOrder(newTestMiddleware("recovery"), -10),
+		newTestMiddleware("cors"))
+
+	// Same orders keep the registration order
+	assert.Equal(t, []string{"recovery", "auth", "cors", "logging"}, names)
+}
+
+func TestWithOrder_SingleResolution(t *testing.T) {
+	container := newTestContainer(t,
+		WithOrder(newTestMiddleware("primary"), 1),
+		newTestMiddleware("fallback"))
+	defer container.Dispose()
+
+	middleware, err := GetService[*testMiddleware](container.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, "primary", middleware.name)
+}
+
+func TestWithBeforeAfter(t *testing.T) {
+	names := resolveTestMiddlewareNames(t,
+		WithName(WithAfter(newTestMiddleware("logging"), "auth"), "logging"),
+		WithName(newTestMiddleware("auth"), "auth"),
+		WithName(WithBefore(newTestMiddleware("recovery"), "logging", "missing"), "recovery"),
+		WithName(WithOrder(newTestMiddleware("cors"), -1), "cors"))
+
+	assert.Equal(t, []string{"cors", "auth", "recovery", "logging"}, names)
+}
+
+func TestWithBeforeAfter_Accumulate(t *testing.T) {
+	descriptor := WithAfter(WithAfter(newTestMiddleware("logging"), "auth"), "cors")
+	after, ok := GetMetadata[[]string](descriptor, AfterMetadataKey)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"auth", "cors"}, after)
+}
+
+func TestOrdering_DisplayNames(t *testing.T) {
+	first := NewTransientServiceFactory[*testMiddleware](NewFactoryWith("first", nil, func(ServiceProvider) (any, error) {
+		return &testMiddleware{name: "first"}, nil
+	}))
+	second := NewTransientServiceFactory[*testMiddleware](NewFactoryWith("second", nil, func(ServiceProvider) (any, error) {
+		return &testMiddleware{name: "second"}, nil
+	}))
+
+	names := resolveTestMiddlewareNames(t, first, WithBefore(second, "first"))
+	assert.Equal(t, []string{"second", "first"}, names)
+}
+
+func TestOrdering_Contradictory(t *testing.T) {
+	services := NewServiceCollection().AddRange(
+		WithName(WithBefore(newTestMiddleware("auth"), "cors"), "auth"),
+		WithName(WithBefore(newTestMiddleware("cors"), "auth"), "cors"),
+		WithName(newTestMiddleware("logging"), "logging"))
+
+	container, err := services.Build()
+	assert.Nil(t, container)
+	assert.ErrorIs(t, err, ErrContradictoryOrder)
+	assert.ErrorContains(t, err, `"auth"`)
+	assert.ErrorContains(t, err, `"cors"`)
+	assert.NotContains(t, err.Error(), `"logging"`)
+
+	// Even without validation
+	_, err = services.BuildWithOptions(BuildOptions{SkipValidation: true})
+	assert.ErrorIs(t, err, ErrContradictoryOrder)
+}
+
+func TestSortDescriptors_KeepsTypePositions(t *testing.T) {
+	other := NewSingletonFactory[*testWarmC](func(ServiceProvider) (any, error) { return &testWarmC{}, nil })
+	first := newTestMiddleware("first")
+	second := WithOrder(newTestMiddleware("second"), -1)
+
+	sorted, err := sortDescriptors([]ServiceDescriptor{first, other, second})
+	assert.NoError(t, err)
+	assert.Equal(t, []ServiceDescriptor{second, other, first}, sorted)
+}
+
+func TestHost_OrderedHostedServices(t *testing.T) {
+	var events []string
+	services := NewServiceCollection()
+	services.Add(NewSingletonFactory[*testHostedDatabase](func(ServiceProvider) (any, error) {
+		return &testHostedDatabase{testHostedService{"database", &events, ""}}, nil
+	}))
+	services.Add(NewSingletonFactory[*testHostedService](func(ServiceProvider) (any, error) {
+		return &testHostedService{"metrics", &events, ""}, nil
+	}))
+	AddHostedService[*testHostedDatabase](services)
+	services.Add(WithOrder(
+		NewTransientFactory[HostedService](func(provider ServiceProvider) (any, error) {
+			return GetService[*testHostedService](provider)
+		}), -1))
+
+	host, err := NewHost(services, HostOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, host.Start(context.Background()))
+	assert.NoError(t, host.Stop(context.Background()))
+
+	assert.Equal(t, []string{"start metrics", "start database", "stop database", "stop metrics"}, events)
+}
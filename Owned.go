@@ -0,0 +1,71 @@
+package di
+
+import "reflect"
+
+// Owned is a service of type T resolved in a dedicated scope, along with the
+// transient and scoped services it requires. Require Owned[T] instead of T
+// to release them with Dispose as soon as the service is not needed anymore,
+// instead of with the scope it was resolved from. Singletons are shared as
+// usual, and the dedicated scope inherits the supplied scope values.
+// The owner is responsible for calling Dispose.
+type Owned[T any] struct {
+	Value T
+	scope Disposable
+}
+
+var _ Disposable = Owned[any]{}
+
+// NewOwned returns the given value owning the given disposable, typically
+// the scope the value was resolved from.
+func NewOwned[T any](value T, scope Disposable) Owned[T] {
+	return Owned[T]{Value: value, scope: scope}
+}
+
+// Dispose implements Disposable, disposing the scope of the owned service.
+func (owned Owned[T]) Dispose() {
+	if owned.scope != nil {
+		owned.scope.Dispose()
+	}
+}
+
+// ownedService is implemented by every Owned type.
+type ownedService interface {
+	ownedServiceType() reflect.Type
+	withInstance(instance any, scope Disposable) any
+}
+
+func (Owned[T]) ownedServiceType() reflect.Type {
+	return typeOf[T]()
+}
+
+func (Owned[T]) withInstance(instance any, scope Disposable) any {
+	value, _ := instance.(T)
+	return NewOwned(value, scope)
+}
+
+var typeOfOwnedService = typeOf[ownedService]()
+
+// unwrapOwned returns T for Owned[T].
+func unwrapOwned(serviceType reflect.Type) (reflect.Type, bool) {
+	if serviceType.Kind() != reflect.Struct || !serviceType.Implements(typeOfOwnedService) {
+		return serviceType, false
+	}
+	return reflect.Zero(serviceType).Interface().(ownedService).ownedServiceType(), true
+}
+
+// newOwned wraps an instance into a value of the given Owned type.
+func newOwned(ownedType reflect.Type, instance any, scope Disposable) any {
+	return reflect.Zero(ownedType).Interface().(ownedService).withInstance(instance, scope)
+}
+
+// newOwnedDescriptor returns the descriptor standing for a requirement on
+// the given Owned type while validating descriptors: its service is
+// resolved from a scope, whatever the lifetime of the requiring service.
+func newOwnedDescriptor(ownedType reflect.Type, serviceType reflect.Type) ServiceDescriptor {
+	return NewScopedServiceFactoryForType(ownedType, NewFactoryWith(
+		ownedType.String(),
+		[]reflect.Type{serviceType},
+		func(provider ServiceProvider) (any, error) {
+			return provider.GetService(ownedType)
+		}))
+}
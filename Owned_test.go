@@ -0,0 +1,159 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testOwnedSession struct {
+	id       int
+	disposed *[]string
+}
+
+func (session *testOwnedSession) Dispose() {
+	*session.disposed = append(*session.disposed, fmt.Sprintf("session %d", session.id))
+}
+
+type testUnitOfWork struct {
+	Session *testOwnedSession
+}
+
+func (work *testUnitOfWork) Dispose() {
+	*work.Session.disposed = append(*work.Session.disposed, fmt.Sprintf("work on session %d", work.Session.id))
+}
+
+type testWorkRunner struct {
+	Work Owned[*testUnitOfWork]
+}
+
+func TestOwned(t *testing.T) {
+	var disposed []string
+	sessions := 0
+	session := NewScopedFactory[*testOwnedSession](func(ServiceProvider) (any, error) {
+		sessions++
+		return &testOwnedSession{id: sessions, disposed: &disposed}, nil
+	})
+	work, _ := NewTransientStructPtr[testUnitOfWork]()
+	container := newTestContainer(t, session, work)
+	defer container.Dispose()
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+	scoped, err := GetService[*testOwnedSession](scope.Provider())
+	assert.NoError(t, err)
+
+	owned, err := GetService[Owned[*testUnitOfWork]](scope.Provider())
+	assert.NoError(t, err)
+	assert.NotSame(t, scoped, owned.Value.Session)
+	assert.Empty(t, disposed)
+
+	owned.Dispose()
+	assert.Equal(t, []string{"work on session 2", "session 2"}, disposed)
+
+	scope.Dispose()
+	assert.Equal(t, []string{"work on session 2", "session 2", "session 1"}, disposed)
+}
+
+func TestOwned_FromSingleton(t *testing.T) {
+	var disposed []string
+	sessions := 0
+	session := NewScopedFactory[*testOwnedSession](func(ServiceProvider) (any, error) {
+		sessions++
+		return &testOwnedSession{id: sessions, disposed: &disposed}, nil
+	})
+	work, _ := NewTransientStructPtr[testUnitOfWork]()
+	runner, _ := NewSingletonStructPtr[testWorkRunner]()
+	container := newTestContainer(t, session, work, runner)
+	defer container.Dispose()
+
+	resolved, err := GetService[*testWorkRunner](container.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resolved.Work.Value.Session.id)
+
+	resolved.Work.Dispose()
+	assert.Equal(t, []string{"work on session 1", "session 1"}, disposed)
+}
+
+func TestOwned_SharesSingletons(t *testing.T) {
+	var disposed []string
+	container, err := NewServiceCollection().Add(NewSingletonFactory[*testOwnedSession](func(ServiceProvider) (any, error) {
+		return &testOwnedSession{id: 1, disposed: &disposed}, nil
+	})).Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	session, err := GetService[*testOwnedSession](container.Provider())
+	assert.NoError(t, err)
+	owned, err := GetService[Owned[*testOwnedSession]](container.Provider())
+	assert.NoError(t, err)
+	assert.Same(t, session, owned.Value)
+
+	owned.Dispose()
+	assert.Empty(t, disposed)
+}
+
+func TestOwned_InheritsScopeValues(t *testing.T) {
+	descriptor, _ := NewTransientStructPtr[testUserGreeting]()
+	container := newTestContainer(t, NewScopeSupplied[*testRequestUser](), descriptor)
+	defer container.Dispose()
+
+	alice := &testRequestUser{name: "alice"}
+	scope, err := container.CreateScopeWith(NewScopeValue(alice))
+	assert.NoError(t, err)
+	defer scope.Dispose()
+
+	owned, err := GetService[Owned[*testUserGreeting]](scope.Provider())
+	assert.NoError(t, err)
+	defer owned.Dispose()
+	assert.Same(t, alice, owned.Value.User)
+}
+
+func TestOwned_OnFailure(t *testing.T) {
+	var disposed []string
+	sessions := 0
+	session := NewScopedFactory[*testOwnedSession](func(ServiceProvider) (any, error) {
+		sessions++
+		return &testOwnedSession{id: sessions, disposed: &disposed}, nil
+	})
+	work, _ := NewTransientStructPtr[testUnitOfWork]()
+	errFailed := errors.New("failed")
+	container := newTestContainer(t, session, work, NewTransientFactory[*testWorkRunner](
+		func(provider ServiceProvider) (any, error) {
+			if _, err := GetService[*testOwnedSession](provider); err != nil {
+				return nil, err
+			}
+			return nil, errFailed
+		}))
+	defer container.Dispose()
+
+	_, err := GetService[Owned[*testWorkRunner]](container.Provider())
+	assert.ErrorIs(t, err, errFailed)
+	assert.Equal(t, []string{"session 1"}, disposed)
+}
+
+func TestOwned_Validation(t *testing.T) {
+	runner, _ := NewSingletonStructPtr[testWorkRunner]()
+	_, err := NewServiceCollection().Add(runner).Build()
+	assert.ErrorContains(t, err, "[Scoped] di.Owned[*github.com/go-mike/di.testUnitOfWork]")
+	assert.ErrorContains(t, err, "=(not found)=> *di.testUnitOfWork fails")
+
+	work, _ := NewTransientStructPtr[testUnitOfWork]()
+	_, err = NewServiceCollection().Add(runner).Add(work).Add(NewScopedFactory[*testOwnedSession](
+		func(ServiceProvider) (any, error) {
+			return &testOwnedSession{}, nil
+		})).Build()
+	assert.NoError(t, err)
+}
+
+func TestNewOwned(t *testing.T) {
+	disposed := 0
+	owned := NewOwned("value", NewDisposable(func() { disposed++ }))
+	assert.Equal(t, "value", owned.Value)
+	owned.Dispose()
+	assert.Equal(t, 1, disposed)
+
+	Owned[string]{}.Dispose()
+}
@@ -0,0 +1,134 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrInvalidParameterizedFactory = errors.New("invalid parameterized factory")
+
+// NewParameterizedFactoryForType creates a transient service descriptor for
+// the function type funcType, such as func(customerID string) (*Report, error),
+// synthesized from the given factory function. Each parameter of funcType is
+// matched by type with a parameter of function, receiving the caller's
+// argument, while the other parameters of function are injected from the
+// scope the function type was resolved from. funcType must return the service
+// type and an error, and its parameter types must be distinct.
+// The instances created by the synthesized function are owned by the caller,
+// which disposes them.
+func NewParameterizedFactoryForType(funcType reflect.Type, function any) (ServiceDescriptor, error) {
+	factory, err := ActivateFuncFactoryForType(function)
+	if err != nil {
+		return nil, err
+	}
+	functionType := reflect.TypeOf(function)
+	if err := checkParameterizedFactory(funcType, functionType); err != nil {
+		return nil, err
+	}
+
+	arguments := rangeMapSlice(0, funcType.NumIn(), funcType.In)
+	requirements := filterSlice(rangeMapSlice(0, functionType.NumIn(), functionType.In),
+		func(paramType reflect.Type) bool {
+			return findSlice(arguments, func(argType reflect.Type) bool { return argType == paramType }) == nil
+		})
+
+	return NewTransientServiceFactoryForType(funcType, withImplementation(
+		NewFactoryWith(
+			getFunctionName(function),
+			append(requirements, typeOfServiceContainer),
+			func(provider ServiceProvider) (any, error) {
+				container, err := GetService[ServiceContainer](provider)
+				if err != nil {
+					return nil, err
+				}
+				return newParameterizedFunc(funcType, factory, container.Provider()), nil
+			}),
+		getFunctionImplementation(function))), nil
+}
+
+// NewParameterizedFactory creates a transient service descriptor for the
+// function type F. See NewParameterizedFactoryForType.
+func NewParameterizedFactory[F any](function any) (ServiceDescriptor, error) {
+	return NewParameterizedFactoryForType(typeOf[F](), function)
+}
+
+// AddParameterizedFactory registers the function type F synthesized from the
+// given factory function. See NewParameterizedFactoryForType.
+func AddParameterizedFactory[F any](services ServiceCollection, function any) error {
+	descriptor, err := NewParameterizedFactory[F](function)
+	if err != nil {
+		return err
+	}
+	services.Add(descriptor)
+	return nil
+}
+
+// checkParameterizedFactory checks that funcType can be synthesized from a
+// factory function of type functionType.
+func checkParameterizedFactory(funcType reflect.Type, functionType reflect.Type) error {
+	if funcType == nil || funcType.Kind() != reflect.Func || funcType.IsVariadic() {
+		return fmt.Errorf("%w: %v is not a function type", ErrInvalidParameterizedFactory, funcType)
+	}
+	if funcType.NumOut() != 2 || funcType.Out(1) != typeOf[error]() {
+		return fmt.Errorf("%w: %v must return a service and an error", ErrInvalidParameterizedFactory, funcType)
+	}
+	if !functionType.Out(0).AssignableTo(funcType.Out(0)) {
+		return fmt.Errorf(
+			"%w: %v is not assignable to %v",
+			ErrInvalidParameterizedFactory, functionType.Out(0), funcType.Out(0))
+	}
+
+	for i := 0; i < funcType.NumIn(); i++ {
+		argType := funcType.In(i)
+		for j := 0; j < i; j++ {
+			if funcType.In(j) == argType {
+				return fmt.Errorf("%w: parameter type %v is ambiguous", ErrInvalidParameterizedFactory, argType)
+			}
+		}
+		if !anySlice(rangeMapSlice(0, functionType.NumIn(), functionType.In),
+			func(paramType reflect.Type) bool { return paramType == argType }) {
+			return fmt.Errorf("%w: parameter type %v is not accepted by the factory", ErrInvalidParameterizedFactory, argType)
+		}
+	}
+
+	return nil
+}
+
+// newParameterizedFunc returns a function of type funcType calling the given
+// factory with its arguments over the given provider.
+func newParameterizedFunc(funcType reflect.Type, factory ServiceFactoryFunc, provider ServiceProvider) any {
+	resultType := funcType.Out(0)
+	return reflect.MakeFunc(funcType, func(args []reflect.Value) []reflect.Value {
+		instance, err := factory(&argumentProvider{provider: provider, arguments: args})
+		result := reflect.Zero(resultType)
+		if !isNil(instance.Instance) {
+			result = reflect.ValueOf(instance.Instance).Convert(resultType)
+		}
+		return []reflect.Value{result, reflect.ValueOf(&err).Elem()}
+	}).Interface()
+}
+
+// argumentProvider is the ServiceProvider given to the factory of a
+// parameterized function, providing its arguments by type.
+type argumentProvider struct {
+	provider  ServiceProvider
+	arguments []reflect.Value
+}
+
+var _ ServiceProvider = (*argumentProvider)(nil)
+
+// GetService implements ServiceProvider
+func (provider *argumentProvider) GetService(serviceType reflect.Type) (any, error) {
+	for _, argument := range provider.arguments {
+		if argument.Type() == serviceType {
+			return argument.Interface(), nil
+		}
+	}
+	return provider.provider.GetService(serviceType)
+}
+
+// GetServiceInfo implements ServiceProvider
+func (provider *argumentProvider) GetServiceInfo(serviceType reflect.Type) ServiceInfo {
+	return provider.provider.GetServiceInfo(serviceType)
+}
@@ -0,0 +1,151 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testReportStore struct {
+	name string
+}
+
+type testReport struct {
+	Store      *testReportStore
+	CustomerID string
+	Year       int
+}
+
+type testReportFactory = func(customerID string, year int) (*testReport, error)
+
+var errTestNoCustomer = errors.New("no customer")
+
+func newTestReport(store *testReportStore, year int, customerID string) (*testReport, error) {
+	if customerID == "" {
+		return nil, errTestNoCustomer
+	}
+	return &testReport{Store: store, CustomerID: customerID, Year: year}, nil
+}
+
+func TestParameterizedFactory(t *testing.T) {
+	services := NewServiceCollection().Add(NewSingletonFactory[*testReportStore](func(ServiceProvider) (any, error) {
+		return &testReportStore{name: "store"}, nil
+	}))
+	assert.NoError(t, AddParameterizedFactory[testReportFactory](services, newTestReport))
+	container, err := services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	create, err := GetService[testReportFactory](container.Provider())
+	assert.NoError(t, err)
+
+	report, err := create("alice", 2024)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", report.CustomerID)
+	assert.Equal(t, 2024, report.Year)
+	store, _ := GetService[*testReportStore](container.Provider())
+	assert.Same(t, store, report.Store)
+
+	other, err := create("bob", 2025)
+	assert.NoError(t, err)
+	assert.NotSame(t, report, other)
+	assert.Equal(t, "bob", other.CustomerID)
+
+	_, err = create("", 2024)
+	assert.ErrorIs(t, err, errTestNoCustomer)
+}
+
+func TestParameterizedFactory_FromScope(t *testing.T) {
+	services := NewServiceCollection().Add(NewScopedFactory[*testReportStore](func(ServiceProvider) (any, error) {
+		return &testReportStore{name: "store"}, nil
+	}))
+	assert.NoError(t, AddParameterizedFactory[testReportFactory](services, newTestReport))
+	container, err := services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	create, err := GetService[testReportFactory](container.Provider())
+	assert.NoError(t, err)
+	_, err = create("alice", 2024)
+	assert.ErrorIs(t, err, ErrScopedServiceFromRoot)
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+	defer scope.Dispose()
+	create, err = GetService[testReportFactory](scope.Provider())
+	assert.NoError(t, err)
+	report, err := create("alice", 2024)
+	assert.NoError(t, err)
+	store, _ := GetService[*testReportStore](scope.Provider())
+	assert.Same(t, store, report.Store)
+
+	scope.Dispose()
+	_, err = create("alice", 2024)
+	assert.ErrorIs(t, err, ErrServiceContainerDisposed)
+}
+
+func TestParameterizedFactory_InterfaceArguments(t *testing.T) {
+	services := NewServiceCollection()
+	err := AddParameterizedFactory[func(io.Reader) (fmt.Stringer, error)](services,
+		func(reader io.Reader) *strings.Builder {
+			builder := &strings.Builder{}
+			if reader != nil {
+				_, _ = io.Copy(builder, reader)
+			}
+			return builder
+		})
+	assert.NoError(t, err)
+	container, err := services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	create, err := GetService[func(io.Reader) (fmt.Stringer, error)](container.Provider())
+	assert.NoError(t, err)
+	result, err := create(strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", result.String())
+	result, err = create(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "", result.String())
+}
+
+func TestParameterizedFactory_MissingDependency(t *testing.T) {
+	services := NewServiceCollection()
+	assert.NoError(t, AddParameterizedFactory[testReportFactory](services, newTestReport))
+	_, err := services.Build()
+	assert.ErrorContains(t, err, "=(not found)=> *di.testReportStore")
+}
+
+func TestNewParameterizedFactory_Invalid(t *testing.T) {
+	_, err := NewParameterizedFactory[testReportFactory](nil)
+	assert.ErrorIs(t, err, ErrInvalidFuncType)
+
+	_, err = NewParameterizedFactory[*testReport](newTestReport)
+	assert.ErrorIs(t, err, ErrInvalidParameterizedFactory)
+
+	_, err = NewParameterizedFactory[func(string, int) *testReport](newTestReport)
+	assert.ErrorIs(t, err, ErrInvalidParameterizedFactory)
+
+	_, err = NewParameterizedFactory[func(string, int) (*testReportStore, error)](newTestReport)
+	assert.ErrorIs(t, err, ErrInvalidParameterizedFactory)
+
+	_, err = NewParameterizedFactory[func(string, string) (*testReport, error)](newTestReport)
+	assert.ErrorContains(t, err, "parameter type string is ambiguous")
+
+	_, err = NewParameterizedFactory[func(string, bool) (*testReport, error)](newTestReport)
+	assert.ErrorContains(t, err, "parameter type bool is not accepted by the factory")
+}
+
+func TestNewParameterizedFactory_Descriptor(t *testing.T) {
+	descriptor, err := NewParameterizedFactory[testReportFactory](newTestReport)
+	assert.NoError(t, err)
+	assert.Equal(t, Transient, descriptor.Lifetime())
+	assert.Equal(t, typeOf[testReportFactory](), descriptor.ServiceType())
+	assert.Equal(t, "newTestReport", descriptor.Factory().DisplayName())
+	assert.Equal(t, []reflect.Type{typeOf[*testReportStore](), typeOfServiceContainer}, descriptor.Factory().Requirements())
+}
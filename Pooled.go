@@ -0,0 +1,147 @@
+package di
+
+import (
+	"reflect"
+	"sync"
+)
+
+// DefaultPoolSize is the number of idle instances kept by the pool of a
+// pooled descriptor without a pool size.
+const DefaultPoolSize = 16
+
+// PoolSizeMetadataKey is the metadata key set by WithPoolSize.
+const PoolSizeMetadataKey = "di.poolSize"
+
+// WithPoolSize returns a descriptor like the given one, whose pool keeps up
+// to size idle instances when the descriptor is pooled. Instances returned
+// to a full pool are disposed.
+func WithPoolSize(descriptor ServiceDescriptor, size int) ServiceDescriptor {
+	return WithMetadata(descriptor, PoolSizeMetadataKey, size)
+}
+
+// Resettable is implemented by pooled services to be reset before they are
+// reused.
+type Resettable interface {
+	Reset()
+}
+
+// PoolStats are statistics about the pool of a pooled descriptor.
+type PoolStats struct {
+	// Capacity is the maximum number of idle instances.
+	Capacity int
+	// Idle is the number of instances waiting in the pool.
+	Idle int
+	// Created counts the instances created by the descriptor's factory.
+	Created uint64
+	// Reused counts the instances taken from the pool.
+	Reused uint64
+	// Returned counts the instances returned to the pool.
+	Returned uint64
+	// Discarded counts the instances disposed instead of being returned,
+	// because the pool was full or the container disposed.
+	Discarded uint64
+}
+
+// poolStatsProvider is implemented by providers able to report pool statistics.
+type poolStatsProvider interface {
+	poolStats(serviceType reflect.Type) (PoolStats, bool)
+}
+
+// GetPoolStatsForType returns the statistics of the pool of the pooled
+// descriptor resolved for the given service type. It returns false when the
+// service type is not pooled.
+func GetPoolStatsForType(provider ServiceProvider, serviceType reflect.Type) (PoolStats, bool) {
+	if statsProvider, ok := provider.(poolStatsProvider); ok {
+		return statsProvider.poolStats(serviceType)
+	}
+	return PoolStats{}, false
+}
+
+// GetPoolStats returns the statistics of the pool of the pooled descriptor
+// resolved for type T. See GetPoolStatsForType.
+func GetPoolStats[T any](provider ServiceProvider) (PoolStats, bool) {
+	return GetPoolStatsForType(provider, typeOf[T]())
+}
+
+// servicePool holds the idle instances of a pooled descriptor.
+type servicePool struct {
+	mutex  sync.Mutex
+	idle   []ServiceInstance
+	closed bool
+	stats  PoolStats
+}
+
+func newServicePool(descriptor ServiceDescriptor) *servicePool {
+	capacity, ok := GetMetadata[int](descriptor, PoolSizeMetadataKey)
+	if !ok {
+		capacity = DefaultPoolSize
+	}
+	return &servicePool{stats: PoolStats{Capacity: max(capacity, 0)}}
+}
+
+// take removes an idle instance from the pool.
+func (pool *servicePool) take() (ServiceInstance, bool) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	if len(pool.idle) == 0 {
+		return ServiceInstance{}, false
+	}
+	instance := pool.idle[len(pool.idle)-1]
+	pool.idle = pool.idle[:len(pool.idle)-1]
+	pool.stats.Reused++
+	return instance, true
+}
+
+// created counts a new instance.
+func (pool *servicePool) created() {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	pool.stats.Created++
+}
+
+// put returns an instance to the pool. It returns false when the instance
+// must be disposed instead.
+func (pool *servicePool) put(instance ServiceInstance) bool {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	if pool.closed || len(pool.idle) >= pool.stats.Capacity {
+		pool.stats.Discarded++
+		return false
+	}
+	pool.idle = append(pool.idle, instance)
+	pool.stats.Returned++
+	return true
+}
+
+// close empties the pool for good, returning the idle instances to dispose.
+func (pool *servicePool) close() []ServiceInstance {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	idle := pool.idle
+	pool.idle = nil
+	pool.closed = true
+	pool.stats.Discarded += uint64(len(idle))
+	return idle
+}
+
+func (pool *servicePool) snapshot() PoolStats {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	stats := pool.stats
+	stats.Idle = len(pool.idle)
+	return stats
+}
+
+// pooledInstance is tracked by the scope a pooled instance was resolved
+// from, to return the instance to its pool when the scope is disposed.
+type pooledInstance struct {
+	pool     *servicePool
+	instance ServiceInstance
+}
+
+// Dispose implements Disposable
+func (pooled *pooledInstance) Dispose() {
+	if !pooled.pool.put(pooled.instance) && pooled.instance.Disposable != nil {
+		pooled.instance.Disposable.Dispose()
+	}
+}
@@ -0,0 +1,256 @@
+package di
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPooledBuffer struct {
+	data     []byte
+	resets   int
+	disposed *int
+}
+
+func (buffer *testPooledBuffer) Reset() {
+	buffer.data = buffer.data[:0]
+	buffer.resets++
+}
+
+func (buffer *testPooledBuffer) Dispose() {
+	*buffer.disposed++
+}
+
+type testPooledConsumer struct {
+	Buffer *testPooledBuffer
+}
+
+func resolveTestPooledBuffers(t *testing.T, scope ServiceContainer, count int) []*testPooledBuffer {
+	buffers := make([]*testPooledBuffer, count)
+	for i := range buffers {
+		buffer, err := GetService[*testPooledBuffer](scope.Provider())
+		assert.NoError(t, err)
+		buffers[i] = buffer
+	}
+	return buffers
+}
+
+func TestPooled_ReusedAcrossScopes(t *testing.T) {
+	disposed := 0
+	descriptor := NewPooledFactory[*testPooledBuffer](func(ServiceProvider) (any, error) {
+		return &testPooledBuffer{disposed: &disposed}, nil
+	})
+	container := newTestContainer(t, descriptor)
+	defer container.Dispose()
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+	buffers := resolveTestPooledBuffers(t, scope, 2)
+	assert.NotSame(t, buffers[0], buffers[1])
+	buffers[0].data = append(buffers[0].data, "dirty"...)
+	scope.Dispose()
+	assert.Equal(t, 0, disposed)
+
+	scope, err = container.CreateScope()
+	assert.NoError(t, err)
+	reused := resolveTestPooledBuffers(t, scope, 2)
+	assert.ElementsMatch(t, buffers, reused)
+	assert.Empty(t, buffers[0].data)
+	assert.Equal(t, 1, buffers[0].resets)
+
+	stats, ok := GetPoolStats[*testPooledBuffer](scope.Provider())
+	assert.True(t, ok)
+	assert.Equal(t, PoolStats{Capacity: DefaultPoolSize, Created: 2, Reused: 2, Returned: 2}, stats)
+
+	scope.Dispose()
+	stats, _ = GetPoolStats[*testPooledBuffer](container.Provider())
+	assert.Equal(t, PoolStats{Capacity: DefaultPoolSize, Idle: 2, Created: 2, Reused: 2, Returned: 4}, stats)
+}
+
+func TestPooled_Bounded(t *testing.T) {
+	disposed := 0
+	descriptor := NewPooledFactory[*testPooledBuffer](func(ServiceProvider) (any, error) {
+		return &testPooledBuffer{disposed: &disposed}, nil
+	})
+	container := newTestContainer(t, WithPoolSize(descriptor, 1))
+	defer container.Dispose()
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+	resolveTestPooledBuffers(t, scope, 3)
+	scope.Dispose()
+	assert.Equal(t, 2, disposed)
+
+	stats, _ := GetPoolStats[*testPooledBuffer](container.Provider())
+	assert.Equal(t, PoolStats{Capacity: 1, Idle: 1, Created: 3, Returned: 1, Discarded: 2}, stats)
+}
+
+func TestPooled_DisposedWithRoot(t *testing.T) {
+	disposed := 0
+	descriptor := NewPooledFactory[*testPooledBuffer](func(ServiceProvider) (any, error) {
+		return &testPooledBuffer{disposed: &disposed}, nil
+	})
+	container := newTestContainer(t, descriptor)
+
+	resolveTestPooledBuffers(t, container, 1)
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+	resolveTestPooledBuffers(t, scope, 2)
+
+	container.Dispose()
+	assert.Equal(t, 1, disposed)
+
+	scope.Dispose()
+	assert.Equal(t, 3, disposed)
+
+	stats, _ := GetPoolStats[*testPooledBuffer](scope.Provider())
+	assert.Equal(t, PoolStats{Capacity: DefaultPoolSize, Created: 3, Discarded: 3}, stats)
+}
+
+type testPooledConnection struct {
+	disposed bool
+}
+
+func (connection *testPooledConnection) Dispose() {
+	connection.disposed = true
+}
+
+type testPooledConnectionCounter struct {
+	mutex    sync.Mutex
+	disposed int
+}
+
+func (counter *testPooledConnectionCounter) Dispose() {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+	counter.disposed++
+}
+
+type testPooledCountedClient struct {
+	Connection *testPooledConnectionCounter
+}
+
+type testPooledClient struct {
+	Connection *testPooledConnection
+}
+
+func TestPooled_DependenciesOutliveScope(t *testing.T) {
+	client, _ := NewPooledStructPtr[testPooledClient]()
+	container, err := NewServiceCollection().Add(client).Add(NewTransientFactory[*testPooledConnection](
+		func(ServiceProvider) (any, error) {
+			return &testPooledConnection{}, nil
+		})).Build()
+	assert.NoError(t, err)
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+	rented, err := GetService[*testPooledClient](scope.Provider())
+	assert.NoError(t, err)
+	scope.Dispose()
+	assert.False(t, rented.Connection.disposed)
+
+	scope, err = container.CreateScope()
+	assert.NoError(t, err)
+	reused, err := GetService[*testPooledClient](scope.Provider())
+	assert.NoError(t, err)
+	assert.Same(t, rented, reused)
+	assert.False(t, reused.Connection.disposed)
+	scope.Dispose()
+
+	container.Dispose()
+	assert.True(t, rented.Connection.disposed)
+}
+
+func TestPooled_Concurrent(t *testing.T) {
+	descriptor := NewPooledFactory[*testPooledBuffer](func(ServiceProvider) (any, error) {
+		return &testPooledBuffer{disposed: new(int)}, nil
+	})
+	container := newTestContainer(t, WithPoolSize(descriptor, 4))
+	defer container.Dispose()
+
+	var wait sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for j := 0; j < 10; j++ {
+				scope, err := container.CreateScope()
+				assert.NoError(t, err)
+				resolveTestPooledBuffers(t, scope, 2)
+				scope.Dispose()
+			}
+		}()
+	}
+	wait.Wait()
+
+	stats, _ := GetPoolStats[*testPooledBuffer](container.Provider())
+	assert.Equal(t, uint64(320), stats.Created+stats.Reused)
+	assert.Equal(t, stats.Created+stats.Reused, stats.Returned+stats.Discarded)
+	assert.LessOrEqual(t, stats.Idle, 4)
+}
+
+func TestPooled_Validation(t *testing.T) {
+	consumer, _ := NewPooledStructPtr[testPooledConsumer]()
+	_, err := NewServiceCollection().Add(consumer).Add(NewScopedFactory[*testPooledBuffer](
+		func(ServiceProvider) (any, error) {
+			return &testPooledBuffer{}, nil
+		})).Build()
+	assert.ErrorContains(t, err, "[Pooled] *di.testPooledConsumer")
+	assert.ErrorContains(t, err, "=(invalid)=> [Scoped] *di.testPooledBuffer")
+
+	singleton, _ := NewSingletonStructPtr[testPooledConsumer]()
+	disposed := 0
+	container, err := NewServiceCollection().Add(singleton).Add(NewPooledFactory[*testPooledBuffer](
+		func(ServiceProvider) (any, error) {
+			return &testPooledBuffer{disposed: &disposed}, nil
+		})).Build()
+	assert.NoError(t, err)
+	_, err = GetService[*testPooledConsumer](container.Provider())
+	assert.NoError(t, err)
+	container.Dispose()
+	assert.Equal(t, 1, disposed)
+}
+
+func TestGetPoolStats_NotPooled(t *testing.T) {
+	container, err := NewServiceCollection().Add(NewTransientFactory[*testPooledBuffer](
+		func(ServiceProvider) (any, error) {
+			return &testPooledBuffer{}, nil
+		})).Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	_, ok := GetPoolStats[*testPooledBuffer](container.Provider())
+	assert.False(t, ok)
+	_, ok = GetPoolStats[*testPooledConsumer](container.Provider())
+	assert.False(t, ok)
+	_, ok = GetPoolStats[*testPooledBuffer](nil)
+	assert.False(t, ok)
+}
+
+func TestPooled_DiscardedWithDependencies(t *testing.T) {
+	client, _ := NewPooledStructPtr[testPooledCountedClient]()
+	counter := &testPooledConnectionCounter{}
+	connections := 0
+	container, err := NewServiceCollection().Add(WithPoolSize(client, 1)).Add(
+		NewTransientFactory[*testPooledConnectionCounter](func(ServiceProvider) (any, error) {
+			connections++
+			return counter, nil
+		})).Build()
+	assert.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		scope, err := container.CreateScope()
+		assert.NoError(t, err)
+		for j := 0; j < 2; j++ {
+			_, err := GetService[*testPooledCountedClient](scope.Provider())
+			assert.NoError(t, err)
+		}
+		scope.Dispose()
+	}
+	assert.Equal(t, 101, connections)
+	assert.Equal(t, 100, counter.disposed)
+
+	container.Dispose()
+	assert.Equal(t, 101, counter.disposed)
+}
@@ -0,0 +1,105 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrScopeValueNotDeclared = errors.New("scope value type not declared as scope-supplied")
+var ErrScopeValueNotSupplied = errors.New("scope value not supplied")
+var ErrInvalidScopeValue = errors.New("invalid scope value")
+
+// ScopeValue is an instance supplied to a scope when it is created, for a
+// service type declared as scope-supplied.
+type ScopeValue struct {
+	ServiceType reflect.Type
+	Instance    any
+}
+
+// NewScopeValue returns the scope value of type T for the given instance.
+func NewScopeValue[T any](instance T) ScopeValue {
+	return ScopeValue{ServiceType: typeOf[T](), Instance: instance}
+}
+
+// NewScopeSuppliedForType creates a scoped service descriptor for a service
+// type whose instances are supplied by CreateScopeWith, such as the
+// authenticated user of a request. Resolving it from a scope it was not
+// supplied to fails with ErrScopeValueNotSupplied. Being scoped, it can only
+// be required by scoped and transient services.
+func NewScopeSuppliedForType(serviceType reflect.Type) ServiceDescriptor {
+	return NewScopedServiceFactoryForType(serviceType, &scopeSuppliedFactory{serviceType: serviceType})
+}
+
+// NewScopeSupplied creates a scope-supplied service descriptor for type T.
+// See NewScopeSuppliedForType.
+func NewScopeSupplied[T any]() ServiceDescriptor {
+	return NewScopeSuppliedForType(typeOf[T]())
+}
+
+// AddScopeSupplied declares T as a scope-supplied service type.
+// See NewScopeSuppliedForType.
+func AddScopeSupplied[T any](services ServiceCollection) ServiceCollection {
+	return services.Add(NewScopeSupplied[T]())
+}
+
+// scopeSuppliedFactory is the factory of scope-supplied descriptors, called
+// only when no instance was supplied.
+type scopeSuppliedFactory struct {
+	serviceType reflect.Type
+}
+
+var _ ServiceFactory = (*scopeSuppliedFactory)(nil)
+
+// Factory implements ServiceFactory
+func (factory *scopeSuppliedFactory) Factory() ServiceFactoryFunc {
+	return func(ServiceProvider) (ServiceInstance, error) {
+		return ServiceInstance{}, ErrScopeValueNotSupplied
+	}
+}
+
+// Requirements implements ServiceFactory
+func (factory *scopeSuppliedFactory) Requirements() []reflect.Type {
+	return []reflect.Type{}
+}
+
+// DisplayName implements ServiceFactory
+func (factory *scopeSuppliedFactory) DisplayName() string {
+	return fmt.Sprintf("supplied %s", factory.serviceType)
+}
+
+func isScopeSupplied(descriptor ServiceDescriptor) bool {
+	_, ok := descriptor.Factory().(*scopeSuppliedFactory)
+	return ok
+}
+
+// scopeValues returns the given values by the scope-supplied descriptor they
+// are supplied for, after the inherited ones.
+func scopeValues(
+	describer ServiceDescriber,
+	inherited map[ServiceDescriptor]any,
+	values []ScopeValue,
+) (map[ServiceDescriptor]any, error) {
+	supplied := make(map[ServiceDescriptor]any, len(inherited)+len(values))
+	for descriptor, instance := range inherited {
+		supplied[descriptor] = instance
+	}
+
+	for _, value := range values {
+		if value.ServiceType == nil {
+			return nil, fmt.Errorf("%w: missing service type", ErrInvalidScopeValue)
+		}
+		descriptor := describer.GetServiceDescriptor(value.ServiceType)
+		if descriptor == nil || !isScopeSupplied(descriptor) {
+			return nil, fmt.Errorf("%w: %s", ErrScopeValueNotDeclared, value.ServiceType)
+		}
+		if value.Instance != nil && !reflect.TypeOf(value.Instance).AssignableTo(value.ServiceType) {
+			return nil, fmt.Errorf(
+				"%w: %s is not assignable to %s",
+				ErrInvalidScopeValue, reflect.TypeOf(value.Instance), value.ServiceType)
+		}
+		supplied[descriptor] = value.Instance
+	}
+
+	return supplied, nil
+}
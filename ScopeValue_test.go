@@ -0,0 +1,133 @@
+package di
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequestUser struct {
+	name string
+}
+
+type testUserGreeting struct {
+	User *testRequestUser
+}
+
+func TestCreateScopeWith(t *testing.T) {
+	descriptor, _ := NewTransientStructPtr[testUserGreeting]()
+	container := newTestContainer(t, NewScopeSupplied[*testRequestUser](), descriptor)
+	defer container.Dispose()
+
+	alice := &testRequestUser{name: "alice"}
+	scope, err := container.CreateScopeWith(NewScopeValue(alice))
+	assert.NoError(t, err)
+	defer scope.Dispose()
+
+	user, err := GetService[*testRequestUser](scope.Provider())
+	assert.NoError(t, err)
+	assert.Same(t, alice, user)
+
+	greeting, err := GetService[*testUserGreeting](scope.Provider())
+	assert.NoError(t, err)
+	assert.Same(t, alice, greeting.User)
+
+	other, err := container.CreateScopeWith(NewScopeValue(&testRequestUser{name: "bob"}))
+	assert.NoError(t, err)
+	defer other.Dispose()
+	user, err = GetService[*testRequestUser](other.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", user.name)
+}
+
+func TestCreateScopeWith_Inherited(t *testing.T) {
+	descriptor, _ := NewTransientStructPtr[testUserGreeting]()
+	container := newTestContainer(t, NewScopeSupplied[*testRequestUser](), descriptor)
+	defer container.Dispose()
+
+	alice := &testRequestUser{name: "alice"}
+	scope, err := container.CreateScopeWith(NewScopeValue(alice))
+	assert.NoError(t, err)
+	defer scope.Dispose()
+
+	child, err := scope.CreateScope()
+	assert.NoError(t, err)
+	defer child.Dispose()
+	user, err := GetService[*testRequestUser](child.Provider())
+	assert.NoError(t, err)
+	assert.Same(t, alice, user)
+
+	bob := &testRequestUser{name: "bob"}
+	overridden, err := scope.CreateScopeWith(NewScopeValue(bob))
+	assert.NoError(t, err)
+	defer overridden.Dispose()
+	user, err = GetService[*testRequestUser](overridden.Provider())
+	assert.NoError(t, err)
+	assert.Same(t, bob, user)
+}
+
+func TestCreateScopeWith_NotSupplied(t *testing.T) {
+	descriptor, _ := NewTransientStructPtr[testUserGreeting]()
+	container := newTestContainer(t, NewScopeSupplied[*testRequestUser](), descriptor)
+	defer container.Dispose()
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+	defer scope.Dispose()
+
+	_, err = GetService[*testRequestUser](scope.Provider())
+	assert.ErrorIs(t, err, ErrScopeValueNotSupplied)
+	_, err = GetService[*testUserGreeting](scope.Provider())
+	assert.ErrorIs(t, err, ErrScopeValueNotSupplied)
+}
+
+func TestCreateScopeWith_Invalid(t *testing.T) {
+	descriptor, _ := NewTransientStructPtr[testUserGreeting]()
+	container := newTestContainer(t, NewScopeSupplied[*testRequestUser](), descriptor)
+	defer container.Dispose()
+
+	_, err := container.CreateScopeWith(NewScopeValue(&testUserGreeting{}))
+	assert.ErrorIs(t, err, ErrScopeValueNotDeclared)
+
+	_, err = container.CreateScopeWith(ScopeValue{
+		ServiceType: reflect.TypeOf(&testRequestUser{}),
+		Instance:    "alice",
+	})
+	assert.ErrorIs(t, err, ErrInvalidScopeValue)
+
+	_, err = container.CreateScopeWith(ScopeValue{Instance: &testRequestUser{}})
+	assert.ErrorIs(t, err, ErrInvalidScopeValue)
+
+	container.Dispose()
+	_, err = container.CreateScopeWith(NewScopeValue(&testRequestUser{}))
+	assert.ErrorIs(t, err, ErrServiceContainerDisposed)
+}
+
+func TestCreateScopeWith_NotDisposed(t *testing.T) {
+	var disposed []string
+	container, err := AddScopeSupplied[*testDisposeRecorder](NewServiceCollection()).Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	scope, err := container.CreateScopeWith(NewScopeValue(&testDisposeRecorder{name: "supplied", disposed: &disposed}))
+	assert.NoError(t, err)
+	_, err = GetService[*testDisposeRecorder](scope.Provider())
+	assert.NoError(t, err)
+	scope.Dispose()
+	assert.Empty(t, disposed)
+}
+
+func TestScopeSupplied_SingletonConsumer(t *testing.T) {
+	greeting, err := NewSingletonStructPtr[testUserGreeting]()
+	assert.NoError(t, err)
+	_, err = AddScopeSupplied[*testRequestUser](NewServiceCollection()).Add(greeting).Build()
+	assert.ErrorContains(t, err, "=(invalid)=>")
+}
+
+func TestScopeSupplied_DisplayName(t *testing.T) {
+	descriptor := NewScopeSupplied[*testRequestUser]()
+	assert.Equal(t, Scoped, descriptor.Lifetime())
+	assert.Equal(t, "supplied *di.testRequestUser", descriptor.Factory().DisplayName())
+	assert.Empty(t, descriptor.Factory().Requirements())
+}
@@ -23,7 +23,51 @@ type ServiceCollection interface {
 	TryAdd(descriptor ServiceDescriptor) ServiceCollection
 	TryAddRange(descriptors ...ServiceDescriptor) ServiceCollection
 
+	// TryAddEnumerable adds a descriptor unless one with the same service type
+	// has the same implementation: the same struct type, function or instance
+	// for factories created from those, or else the same factory display name.
+	// It lets libraries contribute to services resolved as slices without
+	// adding them twice.
+	TryAddEnumerable(descriptor ServiceDescriptor) ServiceCollection
+	TryAddEnumerableRange(descriptors ...ServiceDescriptor) ServiceCollection
+
+	// Replace replaces the first descriptor of the same service type, at its
+	// position, keeping the other descriptors of the type. The descriptor is
+	// added when none has the same service type.
+	Replace(descriptor ServiceDescriptor) ServiceCollection
+	// Remove removes the given descriptor, keeping the order of the others.
+	Remove(descriptor ServiceDescriptor) ServiceCollection
+	// RemoveAll removes every descriptor of the given service type, keeping
+	// the order of the others.
+	RemoveAll(serviceType reflect.Type) ServiceCollection
+
+	// AddModule installs a module once, after the modules it depends on.
+	// Modules depending on modules not added yet are installed when those are added,
+	// and missing module dependencies are reported by Build.
+	AddModule(module Module) ServiceCollection
+
 	// TODO: Decorate
 
+	// AddValidator registers a function run by Build against the root container.
+	// Build disposes the container and fails when any validator fails.
+	AddValidator(validator func(provider ServiceProvider) error) ServiceCollection
+
+	// AddHooks registers callbacks to be invoked by containers built from this collection.
+	AddHooks(hooks ContainerHooks) ServiceCollection
+
 	Build() (ServiceContainer, error)
+	// BuildWithOptions builds the collection into a container, configured
+	// by the given options.
+	BuildWithOptions(options BuildOptions) (ServiceContainer, error)
+}
+
+// ReplaceOf replaces the first descriptor of type T with a descriptor of the
+// given lifetime and factory. See ServiceCollection.Replace.
+func ReplaceOf[T any](services ServiceCollection, lifetime Lifetime, factory ServiceFactory) ServiceCollection {
+	return services.Replace(NewDescriptor[T](lifetime, factory))
+}
+
+// RemoveAllOf removes every descriptor of type T. See ServiceCollection.RemoveAll.
+func RemoveAllOf[T any](services ServiceCollection) ServiceCollection {
+	return services.RemoveAll(typeOf[T]())
 }
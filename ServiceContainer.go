@@ -1,8 +1,18 @@
 package di
 
+// ServiceContainer owns the services instantiated from a service collection.
+// Unless registered otherwise, requiring a ServiceContainer resolves the
+// container instantiating the service: the root container for singletons, and
+// the current scope for scoped and transient services.
 type ServiceContainer interface {
 	Provider() ServiceProvider
 	IsScoped() bool
+	CreateScope() (ServiceContainer, error)
+	// CreateScopeWith creates a scope seeded with instances of scope-supplied
+	// service types. See NewScopeSuppliedForType.
+	CreateScopeWith(values ...ScopeValue) (ServiceContainer, error)
 	Dispose()
 	IsDisposed() bool
 }
+
+var typeOfServiceContainer = typeOf[ServiceContainer]()
@@ -10,4 +10,9 @@ type ServiceDescriptor interface {
 	Lifetime() Lifetime
 	ServiceType() reflect.Type
 	Factory() ServiceFactory
+	// CallSite returns where the descriptor was created, unless disabled
+	// with RecordCallSites.
+	CallSite() CallSite
+	// Metadata returns the values and tags attached with WithMetadata and WithTags.
+	Metadata() Metadata
 }
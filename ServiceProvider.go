@@ -1,12 +1,31 @@
 package di
 
-import "reflect"
+import (
+	"errors"
+	"reflect"
+)
+
+var ErrInvalidServiceType = errors.New("invalid service type")
 
 type ServiceProvider interface {
 	GetService(serviceType reflect.Type) (any, error)
 	GetServiceInfo(serviceType reflect.Type) ServiceInfo
 }
 
+// GetService resolves a service of type T from the given provider.
+func GetService[T any](provider ServiceProvider) (T, error) {
+	var empty T
+	service, err := provider.GetService(typeOf[T]())
+	if err != nil || service == nil {
+		return empty, err
+	}
+	typed, ok := service.(T)
+	if !ok {
+		return empty, ErrInvalidServiceType
+	}
+	return typed, nil
+}
+
 type ServiceInfo struct {
 	ServiceType    reflect.Type
 	Lifetime       Lifetime
@@ -0,0 +1,41 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetService(t *testing.T) {
+	provider := &testStructWithFieldsProvider{}
+
+	value, err := GetService[int](provider)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, value)
+}
+
+func TestGetService_OnError(t *testing.T) {
+	value, err := GetService[int](&testStructWithFailProvider{})
+	assert.Equal(t, errTestFailProvider, err)
+	assert.Zero(t, value)
+}
+
+func TestGetService_OnInvalidType(t *testing.T) {
+	descriptor := NewSingletonFactory[testServiceInterface](func(ServiceProvider) (any, error) {
+		return 42, nil
+	})
+	container := newTestContainer(t, descriptor)
+
+	value, err := GetService[testServiceInterface](container.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, 42, value)
+
+	descriptor = NewSingletonFactory[*testServiceStruct](func(ServiceProvider) (any, error) {
+		return 42, nil
+	})
+	container = newTestContainer(t, descriptor)
+
+	pointer, err := GetService[*testServiceStruct](container.Provider())
+	assert.Equal(t, ErrInvalidServiceType, err)
+	assert.Nil(t, pointer)
+}
@@ -0,0 +1,130 @@
+package di
+
+import (
+	"errors"
+	"reflect"
+)
+
+var ErrWarmUpNotSupported = errors.New("service provider does not support warm-up")
+
+// WarmUpOptions configures the instantiation of singletons by Build.
+type WarmUpOptions struct {
+	// All instantiates every singleton, instead of only the ones marked Eager.
+	All bool
+	// Parallelism is the maximum number of singletons instantiated
+	// concurrently. Singletons only depending on already instantiated ones
+	// are instantiated together. Values below 2 instantiate singletons one
+	// at a time.
+	Parallelism int
+}
+
+// EagerMetadataKey is the metadata key set by Eager.
+const EagerMetadataKey = "di.eager"
+
+// Eager marks a singleton descriptor to be instantiated by Build, when
+// warm-up is enabled with AddWarmUp.
+func Eager(descriptor ServiceDescriptor) ServiceDescriptor {
+	return WithMetadata(descriptor, EagerMetadataKey, true)
+}
+
+// IsEager tells whether a descriptor was marked with Eager.
+func IsEager(descriptor ServiceDescriptor) bool {
+	eager, _ := GetMetadata[bool](descriptor, EagerMetadataKey)
+	return eager
+}
+
+// AddWarmUp makes Build instantiate singletons right after validation, so
+// that failing factories make Build fail instead of the first request.
+// Singletons are instantiated in dependency order, each one exactly once.
+// Singletons depending on a failed one are skipped, and all the failures are
+// returned by Build.
+func AddWarmUp(services ServiceCollection, options WarmUpOptions) ServiceCollection {
+	return services.AddValidator(func(provider ServiceProvider) error {
+		return warmUpSingletons(provider, services.ListDescriptors(), options)
+	})
+}
+
+// warmUpSingletons instantiates the singletons selected by the options.
+func warmUpSingletons(provider ServiceProvider, descriptors []ServiceDescriptor, options WarmUpOptions) error {
+	warmer, ok := provider.(singletonWarmer)
+	if !ok {
+		return ErrWarmUpNotSupported
+	}
+	selected := filterSlice(descriptors, func(descriptor ServiceDescriptor) bool {
+		return descriptor.Lifetime() == Singleton && (options.All || IsEager(descriptor))
+	})
+	return warmer.warmUp(descriptors, selected, options.Parallelism)
+}
+
+// singletonWarmer is implemented by containers able to warm up singletons.
+type singletonWarmer interface {
+	warmUp(descriptors []ServiceDescriptor, selected []ServiceDescriptor, parallelism int) error
+}
+
+// warmUpNode is a singleton to warm up, with the types it requires.
+type warmUpNode struct {
+	descriptor ServiceDescriptor
+	requires   map[reflect.Type]bool
+}
+
+// warmUpLevels groups the selected descriptors in levels, each level only
+// depending on descriptors of the previous levels. Descriptors keep their
+// registration order within a level.
+func warmUpLevels(descriptors []ServiceDescriptor, selected []ServiceDescriptor) ([][]warmUpNode, error) {
+	nodes := mapSlice(selected, func(descriptor ServiceDescriptor) warmUpNode {
+		return warmUpNode{
+			descriptor: descriptor,
+			requires:   reachableRequirements(descriptors, descriptor),
+		}
+	})
+
+	var levels [][]warmUpNode
+	done := make([]bool, len(nodes))
+	remaining := len(nodes)
+	for remaining > 0 {
+		ready := filterSlice(rangeSlice(0, len(nodes)), func(i int) bool {
+			return !done[i] && allSlice(rangeSlice(0, len(nodes)), func(j int) bool {
+				return done[j] || i == j || !nodes[i].requires[nodes[j].descriptor.ServiceType()]
+			})
+		})
+		if len(ready) == 0 {
+			pending := filterSlice(rangeSlice(0, len(nodes)), func(i int) bool { return !done[i] })
+			return nil, newServiceResolutionError(
+				mapSlice(pending, func(i int) ServiceDescriptor { return nodes[i].descriptor }),
+				nodes[pending[0]].descriptor.ServiceType(),
+				ErrCircularDependency)
+		}
+		for _, i := range ready {
+			done[i] = true
+		}
+		remaining -= len(ready)
+		levels = append(levels, mapSlice(ready, func(i int) warmUpNode { return nodes[i] }))
+	}
+
+	return levels, nil
+}
+
+// hasRequirementCycle tells whether a type reachable from the nodes requires
+// itself. Instantiating such types concurrently could deadlock instead of
+// failing, so they are instantiated one at a time.
+func hasRequirementCycle(descriptors []ServiceDescriptor, levels [][]warmUpNode) bool {
+	checked := map[reflect.Type]bool{}
+	for _, level := range levels {
+		for _, node := range level {
+			if node.requires[node.descriptor.ServiceType()] {
+				return true
+			}
+			for serviceType := range node.requires {
+				if checked[serviceType] {
+					continue
+				}
+				checked[serviceType] = true
+				required := searchDescriptor(descriptors, serviceType)
+				if required != nil && reachableRequirements(descriptors, required)[serviceType] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,279 @@
+package di
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testWarmA struct{ b *testWarmB }
+type testWarmB struct{ c *testWarmC }
+type testWarmC struct{}
+
+// addTestWarmServices registers A -> B -> C, recording the factories invoked.
+func addTestWarmServices(services ServiceCollection, invoked *[]string, failing string) {
+	services.Add(NewSingletonServiceFactory[*testWarmA](NewFactoryWith(
+		"A",
+		[]reflect.Type{typeOf[*testWarmB]()},
+		func(provider ServiceProvider) (any, error) {
+			*invoked = append(*invoked, "A")
+			if failing == "A" {
+				return nil, errTestFactory
+			}
+			b, err := GetService[*testWarmB](provider)
+			return &testWarmA{b}, err
+		})))
+	services.Add(NewSingletonServiceFactory[*testWarmB](NewFactoryWith(
+		"B",
+		[]reflect.Type{typeOf[*testWarmC]()},
+		func(provider ServiceProvider) (any, error) {
+			*invoked = append(*invoked, "B")
+			if failing == "B" {
+				return nil, errTestFactory
+			}
+			c, err := GetService[*testWarmC](provider)
+			return &testWarmB{c}, err
+		})))
+	services.Add(NewSingletonFactory[*testWarmC](func(ServiceProvider) (any, error) {
+		*invoked = append(*invoked, "C")
+		if failing == "C" {
+			return nil, errTestFactory
+		}
+		return &testWarmC{}, nil
+	}))
+}
+
+func TestAddWarmUp_All(t *testing.T) {
+	var invoked []string
+	services := NewServiceCollection()
+	addTestWarmServices(services, &invoked, "")
+	services.Add(NewScopedFactory[*testStructWithFields](func(ServiceProvider) (any, error) {
+		invoked = append(invoked, "scoped")
+		return &testStructWithFields{}, nil
+	}))
+	AddWarmUp(services, WarmUpOptions{All: true})
+
+	container, err := services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	// Dependencies first, scoped services left alone
+	assert.Equal(t, []string{"C", "B", "A"}, invoked)
+	assert.True(t, container.Provider().GetServiceInfo(typeOf[*testWarmA]()).IsInstantiated)
+}
+
+func TestAddWarmUp_EagerOnly(t *testing.T) {
+	services := NewServiceCollection()
+	services.Add(Eager(NewSingletonFactory[*testWarmC](func(ServiceProvider) (any, error) {
+		return &testWarmC{}, nil
+	})))
+	services.Add(NewSingletonFactory[*testStructWithFields](func(ServiceProvider) (any, error) {
+		return &testStructWithFields{}, nil
+	}))
+	AddWarmUp(services, WarmUpOptions{})
+
+	container, err := services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	provider := container.Provider()
+	assert.True(t, provider.GetServiceInfo(typeOf[*testWarmC]()).IsInstantiated)
+	assert.False(t, provider.GetServiceInfo(typeOf[*testStructWithFields]()).IsInstantiated)
+}
+
+func TestAddWarmUp_AggregatesErrors(t *testing.T) {
+	var invoked []string
+	services := NewServiceCollection()
+	addTestWarmServices(services, &invoked, "B")
+	services.Add(NewSingletonFactory[*testStructWithFields](func(ServiceProvider) (any, error) {
+		return nil, errTestFactory
+	}))
+	AddWarmUp(services, WarmUpOptions{All: true})
+
+	container, err := services.Build()
+	assert.Nil(t, container)
+	assert.ErrorIs(t, err, errTestFactory)
+	// A is skipped as it depends on B
+	assert.Equal(t, []string{"C", "B"}, invoked)
+	assert.Contains(t, err.Error(), "*di.testWarmB")
+	assert.Contains(t, err.Error(), "*di.testStructWithFields")
+}
+
+func TestBuild_WithoutWarmUp(t *testing.T) {
+	var invoked []string
+	services := NewServiceCollection()
+	addTestWarmServices(services, &invoked, "")
+	services.Add(Eager(NewSingletonFactory[*testStructWithFields](func(ServiceProvider) (any, error) {
+		invoked = append(invoked, "eager")
+		return &testStructWithFields{}, nil
+	})))
+
+	container, err := services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	assert.Empty(t, invoked)
+}
+
+func TestEager(t *testing.T) {
+	descriptor := NewSingletonFactory[*testWarmC](func(ServiceProvider) (any, error) {
+		return &testWarmC{}, nil
+	})
+	eager := Eager(descriptor)
+
+	assert.False(t, IsEager(descriptor))
+	assert.True(t, IsEager(eager))
+	assert.Equal(t, descriptor.ServiceType(), eager.ServiceType())
+	assert.Equal(t, descriptor.Lifetime(), eager.Lifetime())
+	assert.Equal(t, descriptor.String(), eager.String())
+}
+
+func TestWarmUpLevels(t *testing.T) {
+	var invoked []string
+	services := NewServiceCollection()
+	addTestWarmServices(services, &invoked, "")
+	services.Add(NewSingletonFactory[*testStructWithFields](func(ServiceProvider) (any, error) {
+		return &testStructWithFields{}, nil
+	}))
+	descriptors := services.ListDescriptors()
+
+	levels, err := warmUpLevels(descriptors, descriptors)
+	assert.NoError(t, err)
+	names := mapSlice(levels, func(level []warmUpNode) []string {
+		return mapSlice(level, func(node warmUpNode) string { return node.descriptor.ServiceType().String() })
+	})
+	assert.Equal(t, [][]string{
+		{"*di.testWarmC", "*di.testStructWithFields"},
+		{"*di.testWarmB"},
+		{"*di.testWarmA"},
+	}, names)
+}
+
+func TestWarmUpLevels_Circular(t *testing.T) {
+	descriptors := []ServiceDescriptor{
+		NewSingletonServiceFactory[*testWarmA](NewFactoryWith("A", []reflect.Type{typeOf[*testWarmB]()}, nil)),
+		NewSingletonServiceFactory[*testWarmB](NewFactoryWith("B", []reflect.Type{typeOf[*testWarmA]()}, nil)),
+	}
+
+	_, err := warmUpLevels(descriptors, descriptors)
+	assert.ErrorIs(t, err, ErrCircularDependency)
+}
+
+// addTestParallelServices registers count independent singletons of distinct
+// types, all requiring *testWarmC, tracking the concurrent instantiations.
+func addTestParallelServices(services ServiceCollection, count int, failing map[int]bool) *testConcurrency {
+	concurrency := &testConcurrency{}
+	for i := 0; i < count; i++ {
+		i := i
+		serviceType := reflect.ArrayOf(i+1, typeOf[int]())
+		services.Add(Eager(NewSingletonServiceFactoryForType(serviceType, NewFactoryWith(
+			serviceType.String(),
+			[]reflect.Type{typeOf[*testWarmC]()},
+			func(provider ServiceProvider) (any, error) {
+				defer concurrency.enter()()
+				if _, err := GetService[*testWarmC](provider); err != nil {
+					return nil, err
+				}
+				time.Sleep(20 * time.Millisecond)
+				if failing[i] {
+					return nil, errTestFactory
+				}
+				return reflect.New(serviceType).Elem().Interface(), nil
+			}))))
+	}
+	services.Add(NewSingletonFactory[*testWarmC](func(ServiceProvider) (any, error) {
+		concurrency.created.Add(1)
+		return &testWarmC{}, nil
+	}))
+	return concurrency
+}
+
+type testConcurrency struct {
+	current atomic.Int32
+	max     atomic.Int32
+	created atomic.Int32
+}
+
+func (concurrency *testConcurrency) enter() func() {
+	current := concurrency.current.Add(1)
+	for {
+		max := concurrency.max.Load()
+		if current <= max || concurrency.max.CompareAndSwap(max, current) {
+			break
+		}
+	}
+	return func() { concurrency.current.Add(-1) }
+}
+
+func TestAddWarmUp_Parallel(t *testing.T) {
+	services := NewServiceCollection()
+	concurrency := addTestParallelServices(services, 8, nil)
+	AddWarmUp(services, WarmUpOptions{Parallelism: 4})
+
+	container, err := services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	assert.Equal(t, int32(4), concurrency.max.Load())
+	// The shared dependency is created once
+	assert.Equal(t, int32(1), concurrency.created.Load())
+	for i := 0; i < 8; i++ {
+		assert.True(t, container.Provider().GetServiceInfo(reflect.ArrayOf(i+1, typeOf[int]())).IsInstantiated)
+	}
+}
+
+func TestAddWarmUp_Sequential(t *testing.T) {
+	services := NewServiceCollection()
+	concurrency := addTestParallelServices(services, 3, nil)
+	AddWarmUp(services, WarmUpOptions{})
+
+	container, err := services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	assert.Equal(t, int32(1), concurrency.max.Load())
+}
+
+func TestAddWarmUp_ParallelCollectsErrors(t *testing.T) {
+	services := NewServiceCollection()
+	addTestParallelServices(services, 6, map[int]bool{1: true, 4: true})
+	var instantiated atomic.Int32
+	services.AddHooks(ContainerHooks{
+		OnInstantiated: func(event InstantiatedEvent) {
+			if event.Err == nil {
+				instantiated.Add(1)
+			}
+		},
+	})
+	AddWarmUp(services, WarmUpOptions{Parallelism: 6})
+
+	container, err := services.Build()
+	assert.Nil(t, container)
+	assert.ErrorIs(t, err, errTestFactory)
+	assert.Contains(t, err.Error(), "[2]int")
+	assert.Contains(t, err.Error(), "[5]int")
+	// The other singletons, and their shared dependency, were still created
+	assert.Equal(t, int32(5), instantiated.Load())
+}
+
+func TestHasRequirementCycle(t *testing.T) {
+	cyclic := []ServiceDescriptor{
+		NewSingletonServiceFactory[*testWarmA](NewFactoryWith("A", []reflect.Type{typeOf[*testWarmB]()}, nil)),
+		NewSingletonServiceFactory[*testWarmB](NewFactoryWith("B", []reflect.Type{typeOf[*testWarmC]()}, nil)),
+		NewSingletonServiceFactory[*testWarmC](NewFactoryWith("C", []reflect.Type{typeOf[*testWarmB]()}, nil)),
+	}
+	levels, err := warmUpLevels(cyclic, cyclic[:1])
+	assert.NoError(t, err)
+	assert.True(t, hasRequirementCycle(cyclic, levels))
+
+	var invoked []string
+	services := NewServiceCollection()
+	addTestWarmServices(services, &invoked, "")
+	descriptors := services.ListDescriptors()
+	levels, err = warmUpLevels(descriptors, descriptors)
+	assert.NoError(t, err)
+	assert.False(t, hasRequirementCycle(descriptors, levels))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// annotationPrefix starts the comments registering a constructor or struct.
+const annotationPrefix = "//di:"
+
+var errInvalidAnnotation = errors.New("invalid annotation")
+
+// annotation is a parsed //di: comment, such as
+//
+//	//di:singleton as=Repo name=primary order=1 tags=sql,cache eager
+type annotation struct {
+	// Lifetime is the di constant of the lifetime: Singleton, Scoped, Transient,
+	// Pooled or PerResolution.
+	Lifetime string
+	// As is the name of the service type, in the annotated package.
+	As string
+	// Name is passed to di.WithName.
+	Name string
+	// Order is passed to di.WithOrder.
+	Order int
+	// HasOrder tells whether Order was set.
+	HasOrder bool
+	// Before and After are passed to di.WithBefore and di.WithAfter.
+	Before []string
+	After  []string
+	// Tags are passed to di.WithTags.
+	Tags []string
+	// Eager wraps the descriptor with di.Eager.
+	Eager bool
+	// Metadata holds the other key=value options, passed to di.WithMetadata.
+	Metadata [][2]string
+}
+
+var annotationLifetimes = map[string]string{
+	"singleton":     "Singleton",
+	"scoped":        "Scoped",
+	"transient":     "Transient",
+	"pooled":        "Pooled",
+	"perresolution": "PerResolution",
+}
+
+// parseAnnotation parses a comment line. It returns false when the line is
+// not an annotation.
+func parseAnnotation(line string) (annotation, bool, error) {
+	if !strings.HasPrefix(line, annotationPrefix) {
+		return annotation{}, false, nil
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, annotationPrefix))
+	if len(fields) == 0 {
+		return annotation{}, true, fmt.Errorf("%w: missing lifetime", errInvalidAnnotation)
+	}
+	lifetime, ok := annotationLifetimes[fields[0]]
+	if !ok {
+		return annotation{}, true, fmt.Errorf("%w: unknown lifetime %q", errInvalidAnnotation, fields[0])
+	}
+
+	result := annotation{Lifetime: lifetime}
+	for _, field := range fields[1:] {
+		key, value, hasValue := strings.Cut(field, "=")
+		if !hasValue {
+			if key != "eager" {
+				return annotation{}, true, fmt.Errorf("%w: option %q has no value", errInvalidAnnotation, key)
+			}
+			result.Eager = true
+			continue
+		}
+		if key == "" || value == "" {
+			return annotation{}, true, fmt.Errorf("%w: malformed option %q", errInvalidAnnotation, field)
+		}
+
+		switch key {
+		case "as":
+			result.As = value
+		case "name":
+			result.Name = value
+		case "order":
+			order, err := strconv.Atoi(value)
+			if err != nil {
+				return annotation{}, true, fmt.Errorf("%w: order %q is not an integer", errInvalidAnnotation, value)
+			}
+			result.Order = order
+			result.HasOrder = true
+		case "before":
+			result.Before = append(result.Before, strings.Split(value, ",")...)
+		case "after":
+			result.After = append(result.After, strings.Split(value, ",")...)
+		case "tags":
+			result.Tags = append(result.Tags, strings.Split(value, ",")...)
+		default:
+			result.Metadata = append(result.Metadata, [2]string{key, value})
+		}
+	}
+
+	return result, true, nil
+}
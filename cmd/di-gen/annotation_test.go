@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAnnotation(t *testing.T) {
+	parsed, ok, err := parseAnnotation("//di:singleton as=Repo name=primary order=-1 before=a,b after=c tags=x,y key=primary eager")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, annotation{
+		Lifetime: "Singleton",
+		As:       "Repo",
+		Name:     "primary",
+		Order:    -1,
+		HasOrder: true,
+		Before:   []string{"a", "b"},
+		After:    []string{"c"},
+		Tags:     []string{"x", "y"},
+		Eager:    true,
+		Metadata: [][2]string{{"key", "primary"}},
+	}, parsed)
+
+	parsed, ok, err = parseAnnotation("//di:scoped")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, annotation{Lifetime: "Scoped"}, parsed)
+
+	parsed, ok, err = parseAnnotation("//di:pooled")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, annotation{Lifetime: "Pooled"}, parsed)
+
+	parsed, ok, err = parseAnnotation("//di:perresolution")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, annotation{Lifetime: "PerResolution"}, parsed)
+
+	for _, line := range []string{"// di:singleton", "// NewRepo creates a repository.", "//go:generate di-gen"} {
+		_, ok, err = parseAnnotation(line)
+		assert.NoError(t, err, line)
+		assert.False(t, ok, line)
+	}
+}
+
+func TestParseAnnotation_Invalid(t *testing.T) {
+	for line, message := range map[string]string{
+		"//di:":                         "missing lifetime",
+		"//di:forever":                  `unknown lifetime "forever"`,
+		"//di:transient lazy":           `option "lazy" has no value`,
+		"//di:transient as=":            `malformed option "as="`,
+		"//di:transient order=first":    `order "first" is not an integer`,
+		"//di:singleton =value as=Repo": `malformed option "=value"`,
+	} {
+		_, ok, err := parseAnnotation(line)
+		assert.True(t, ok, line)
+		assert.ErrorIs(t, err, errInvalidAnnotation, line)
+		assert.ErrorContains(t, err, message, line)
+	}
+}
@@ -0,0 +1,630 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var errInvalidContainer = errors.New("invalid compiled container")
+
+// containerMethods are the methods of compiled containers, which service
+// accessors cannot be named after.
+var containerMethods = map[string]bool{
+	"Provider":       true,
+	"IsScoped":       true,
+	"CreateScope":    true,
+	"Dispose":        true,
+	"IsDisposed":     true,
+	"GetService":     true,
+	"GetServiceInfo": true,
+}
+
+// serviceGroup holds the registrations of a service type, in resolution
+// order: the last one is resolved as a single service.
+type serviceGroup struct {
+	serviceType   types.Type
+	expression    string
+	accessor      string
+	registrations []int
+}
+
+// dependencyKind tells how a compiled container resolves a requirement.
+type dependencyKind int
+
+const (
+	// dependencyService resolves the last registration of a group.
+	dependencyService dependencyKind = iota
+	// dependencySlice resolves every registration of a group.
+	dependencySlice
+	// dependencyContainer resolves the container creating the service.
+	dependencyContainer
+	// dependencyEmptySlice resolves a slice of a service type never registered.
+	dependencyEmptySlice
+)
+
+type dependency struct {
+	kind  dependencyKind
+	group *serviceGroup
+}
+
+// containerGenerator writes a compiled container of the registrations
+// collected by a generator, resolving them with plain function calls instead
+// of reflection.
+type containerGenerator struct {
+	*generator
+	name   string
+	groups []*serviceGroup
+	// dependencies holds the dependencies of each registration.
+	dependencies [][]dependency
+}
+
+func newContainerGenerator(gen *generator, name string) (*containerGenerator, error) {
+	container := &containerGenerator{generator: gen, name: name}
+	if err := container.groupRegistrations(); err != nil {
+		return nil, err
+	}
+	if err := container.nameAccessors(); err != nil {
+		return nil, err
+	}
+	if err := container.resolveDependencies(); err != nil {
+		return nil, err
+	}
+	if err := container.validate(); err != nil {
+		return nil, err
+	}
+	return container, nil
+}
+
+// groupRegistrations groups the registrations by service type, ordered as
+// di.WithOrder orders them.
+func (gen *containerGenerator) groupRegistrations() error {
+	for i, registration := range gen.registrations {
+		if len(registration.Before) > 0 || len(registration.After) > 0 {
+			return fmt.Errorf(
+				"%s: %w: before and after options are not supported",
+				registration.Position, errInvalidContainer)
+		}
+		if registration.Lifetime == "Pooled" || registration.Lifetime == "PerResolution" {
+			return fmt.Errorf(
+				"%s: %w: %s lifetime is not supported",
+				registration.Position, errInvalidContainer, strings.ToLower(registration.Lifetime))
+		}
+		group := gen.group(registration.serviceType)
+		if group == nil {
+			group = &serviceGroup{serviceType: registration.serviceType, expression: registration.ServiceType}
+			gen.groups = append(gen.groups, group)
+		}
+		group.registrations = append(group.registrations, i)
+	}
+
+	for _, group := range gen.groups {
+		sort.SliceStable(group.registrations, func(i, j int) bool {
+			return gen.registrations[group.registrations[i]].Order < gen.registrations[group.registrations[j]].Order
+		})
+	}
+	return nil
+}
+
+func (gen *containerGenerator) group(serviceType types.Type) *serviceGroup {
+	for _, group := range gen.groups {
+		if types.Identical(group.serviceType, serviceType) {
+			return group
+		}
+	}
+	return nil
+}
+
+// nameAccessors names the accessor of each service type after its type name,
+// qualified by its package name when names collide.
+func (gen *containerGenerator) nameAccessors() error {
+	used := map[string]*serviceGroup{}
+	for _, group := range gen.groups {
+		named := namedType(group.serviceType)
+		if named == nil {
+			return fmt.Errorf(
+				"%s: %w: no accessor can be named after service type %s",
+				gen.registrations[group.registrations[0]].Position, errInvalidContainer, group.expression)
+		}
+		group.accessor = exported(named.Obj().Name())
+		if used[group.accessor] != nil || containerMethods[group.accessor] {
+			if pkg := named.Obj().Pkg(); pkg != nil && pkg.Path() != gen.pkg.PkgPath {
+				group.accessor = exported(pkg.Name()) + group.accessor
+			}
+		}
+		if used[group.accessor] != nil || containerMethods[group.accessor] {
+			return fmt.Errorf(
+				"%s: %w: accessor %s of service type %s is already used",
+				gen.registrations[group.registrations[0]].Position, errInvalidContainer, group.accessor, group.expression)
+		}
+		used[group.accessor] = group
+	}
+	return nil
+}
+
+func namedType(t types.Type) *types.Named {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	named, _ := t.(*types.Named)
+	return named
+}
+
+func exported(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func (gen *containerGenerator) resolveDependencies() error {
+	gen.dependencies = make([][]dependency, len(gen.registrations))
+	for i, registration := range gen.registrations {
+		for _, requirement := range registration.requirements {
+			dependency, ok := gen.dependency(requirement)
+			if !ok {
+				return fmt.Errorf(
+					"%s: %w: %s requires %s, which is not registered",
+					registration.Position, errInvalidContainer, registration.ServiceType, gen.typeString(requirement))
+			}
+			gen.dependencies[i] = append(gen.dependencies[i], dependency)
+		}
+	}
+	return nil
+}
+
+func (gen *containerGenerator) dependency(requirement types.Type) (dependency, bool) {
+	if group := gen.group(requirement); group != nil {
+		return dependency{kind: dependencyService, group: group}, true
+	}
+	if slice, ok := requirement.(*types.Slice); ok {
+		if group := gen.group(slice.Elem()); group != nil {
+			return dependency{kind: dependencySlice, group: group}, true
+		}
+		return dependency{kind: dependencyEmptySlice}, true
+	}
+	if isServiceContainer(requirement) {
+		return dependency{kind: dependencyContainer}, true
+	}
+	return dependency{}, false
+}
+
+// registersServiceContainer tells whether di.ServiceContainer is registered,
+// replacing the container itself.
+func (gen *containerGenerator) registersServiceContainer() bool {
+	for _, group := range gen.groups {
+		if isServiceContainer(group.serviceType) {
+			return true
+		}
+	}
+	return false
+}
+
+func isServiceContainer(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == diImportPath && named.Obj().Name() == "ServiceContainer"
+}
+
+// required returns the registrations resolved for a dependency.
+func (dependency dependency) required() []int {
+	switch dependency.kind {
+	case dependencyService:
+		return dependency.group.registrations[len(dependency.group.registrations)-1:]
+	case dependencySlice:
+		return dependency.group.registrations
+	default:
+		return nil
+	}
+}
+
+// validate rejects circular dependencies and singletons requiring scoped
+// services, which the reflective container rejects when built.
+func (gen *containerGenerator) validate() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make([]int, len(gen.registrations))
+	var visit func(i int, path []int) error
+	visit = func(i int, path []int) error {
+		path = append(path, i)
+		switch states[i] {
+		case visiting:
+			names := make([]string, len(path))
+			for j, k := range path {
+				names[j] = gen.registrations[k].ServiceType
+			}
+			return fmt.Errorf(
+				"%s: %w: circular dependency %s",
+				gen.registrations[i].Position, errInvalidContainer, strings.Join(names, " => "))
+		case visited:
+			return nil
+		}
+		states[i] = visiting
+		for _, dependency := range gen.dependencies[i] {
+			for _, j := range dependency.required() {
+				if err := visit(j, path); err != nil {
+					return err
+				}
+			}
+		}
+		states[i] = visited
+		return nil
+	}
+	for i := range gen.registrations {
+		if err := visit(i, nil); err != nil {
+			return err
+		}
+	}
+
+	for i, registration := range gen.registrations {
+		if registration.Lifetime == "Singleton" && gen.requiresScoped(i) {
+			return fmt.Errorf(
+				"%s: %w: singleton %s requires a scoped service",
+				registration.Position, errInvalidContainer, registration.ServiceType)
+		}
+	}
+	return nil
+}
+
+// requiresScoped tells whether a registration requires a scoped service,
+// directly or through transient services.
+func (gen *containerGenerator) requiresScoped(i int) bool {
+	for _, dependency := range gen.dependencies[i] {
+		for _, j := range dependency.required() {
+			switch gen.registrations[j].Lifetime {
+			case "Scoped":
+				return true
+			case "Transient":
+				if gen.requiresScoped(j) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isCached tells whether a registration is created once per container.
+func (registration registration) isCached() bool {
+	return registration.Lifetime != "Transient"
+}
+
+// write writes the compiled container type and its methods.
+func (gen *containerGenerator) write(w io.Writer) {
+	gen.std["reflect"] = true
+	gen.std["sync"] = true
+	gen.std["sync/atomic"] = true
+	gen.std["fmt"] = true
+	name := gen.name
+	typesVar := unexported(name) + "Types"
+	sliceTypesVar := unexported(name) + "SliceTypes"
+
+	fmt.Fprintf(w, `
+// %[1]s is a compiled service container of the services annotated in
+// package %[2]s, resolving them without reflection. It resolves the same
+// services as a container built from the generated registrations, without
+// their metadata, hooks and build options.
+type %[1]s struct {
+	root        *%[1]s
+	mutex       sync.Mutex
+	disposed    bool
+	disposables []di.Disposable
+`, name, gen.pkg.Name)
+	for i, registration := range gen.registrations {
+		if registration.isCached() {
+			fmt.Fprintf(w, "\nservice%[1]d %[2]s\ncreated%[1]d atomic.Bool\nmutex%[1]d sync.Mutex\n", i, registration.ServiceType)
+		}
+	}
+	fmt.Fprintf(w, `}
+
+var _ di.ServiceContainer = (*%[1]s)(nil)
+var _ di.ServiceProvider = (*%[1]s)(nil)
+
+// %[2]s are the service types of %[1]s, and %[3]s the slices of them.
+var (
+	%[2]s = [...]reflect.Type{
+`, name, typesVar, sliceTypesVar)
+	for _, group := range gen.groups {
+		fmt.Fprintf(w, "reflect.TypeOf((*%s)(nil)).Elem(),\n", group.expression)
+	}
+	fmt.Fprintf(w, "}\n%s = [...]reflect.Type{\n", sliceTypesVar)
+	for _, group := range gen.groups {
+		fmt.Fprintf(w, "reflect.TypeOf((*[]%s)(nil)).Elem(),\n", group.expression)
+	}
+	fmt.Fprintf(w, "}\n%sServiceContainerType = reflect.TypeOf((*di.ServiceContainer)(nil)).Elem()\n)\n", unexported(name))
+
+	fmt.Fprintf(w, `
+// New%[1]s creates a root %[1]s.
+func New%[1]s() *%[1]s {
+	container := &%[1]s{}
+	container.root = container
+	return container
+}
+
+// Provider implements di.ServiceContainer
+func (container *%[1]s) Provider() di.ServiceProvider {
+	return container
+}
+
+// IsScoped implements di.ServiceContainer
+func (container *%[1]s) IsScoped() bool {
+	return container.root != container
+}
+
+// CreateScope implements di.ServiceContainer
+func (container *%[1]s) CreateScope() (di.ServiceContainer, error) {
+	if container.IsDisposed() {
+		return nil, di.ErrServiceContainerDisposed
+	}
+	return &%[1]s{root: container.root}, nil
+}
+
+// CreateScopeWith implements di.ServiceContainer. Compiled containers declare
+// no scope-supplied service types, so no value can be supplied.
+func (container *%[1]s) CreateScopeWith(values ...di.ScopeValue) (di.ServiceContainer, error) {
+	if len(values) > 0 {
+		return nil, fmt.Errorf("%%w: %%v", di.ErrScopeValueNotDeclared, values[0].ServiceType)
+	}
+	return container.CreateScope()
+}
+
+// Dispose implements di.ServiceContainer.
+// Instances are disposed in reverse creation order, and a panicking instance
+// does not prevent the remaining ones from being disposed.
+func (container *%[1]s) Dispose() {
+	container.mutex.Lock()
+	if container.disposed {
+		container.mutex.Unlock()
+		return
+	}
+	container.disposed = true
+	disposables := container.disposables
+	container.disposables = nil
+	container.mutex.Unlock()
+
+	for i := len(disposables) - 1; i >= 0; i-- {
+		container.dispose(disposables[i])
+	}
+}
+
+// IsDisposed implements di.ServiceContainer
+func (container *%[1]s) IsDisposed() bool {
+	container.mutex.Lock()
+	defer container.mutex.Unlock()
+	return container.disposed
+}
+
+// track registers a disposable instance to be disposed with the container.
+// When the container is already disposed, the instance is disposed right away.
+func (container *%[1]s) track(instance any) error {
+	disposable, ok := instance.(di.Disposable)
+	if !ok {
+		return nil
+	}
+
+	container.mutex.Lock()
+	if container.disposed {
+		container.mutex.Unlock()
+		container.dispose(disposable)
+		return di.ErrServiceContainerDisposed
+	}
+	container.disposables = append(container.disposables, disposable)
+	container.mutex.Unlock()
+	return nil
+}
+
+func (container *%[1]s) dispose(disposable di.Disposable) {
+	defer func() {
+		_ = recover()
+	}()
+	disposable.Dispose()
+}
+`, name)
+
+	gen.writeGetService(w, typesVar, sliceTypesVar)
+	gen.writeGetServiceInfo(w, typesVar)
+	for _, group := range gen.groups {
+		gen.writeAccessors(w, group)
+	}
+	for i := range gen.registrations {
+		gen.writeGet(w, i)
+		gen.writeCreate(w, i)
+	}
+}
+
+func unexported(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+func (gen *containerGenerator) writeGetService(w io.Writer, typesVar, sliceTypesVar string) {
+	fmt.Fprintf(w, `
+// GetService implements di.ServiceProvider
+func (container *%s) GetService(serviceType reflect.Type) (any, error) {
+	if container.IsDisposed() {
+		return nil, di.ErrServiceContainerDisposed
+	}
+
+	var service any
+	var err error
+	switch serviceType {
+`, gen.name)
+	for g, group := range gen.groups {
+		last := group.registrations[len(group.registrations)-1]
+		fmt.Fprintf(w, "case %s[%d]:\nservice, err = container.get%d()\n", typesVar, g, last)
+		fmt.Fprintf(w, "case %s[%d]:\nservice, err = container.all%s()\n", sliceTypesVar, g, group.accessor)
+	}
+	if !gen.registersServiceContainer() {
+		fmt.Fprintf(w, "case %sServiceContainerType:\nreturn container, nil\n", unexported(gen.name))
+	}
+	fmt.Fprintf(w, `default:
+		if serviceType != nil && serviceType.Kind() == reflect.Slice {
+			return reflect.MakeSlice(serviceType, 0, 0).Interface(), nil
+		}
+		return nil, fmt.Errorf("%%w: %%v", di.ErrServiceNotFound, serviceType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+`)
+}
+
+func (gen *containerGenerator) writeGetServiceInfo(w io.Writer, typesVar string) {
+	fmt.Fprintf(w, `
+// GetServiceInfo implements di.ServiceProvider
+func (container *%s) GetServiceInfo(serviceType reflect.Type) di.ServiceInfo {
+	info := di.ServiceInfo{ServiceType: serviceType}
+	if container.IsDisposed() {
+		return info
+	}
+
+	switch serviceType {
+`, gen.name)
+	for g, group := range gen.groups {
+		last := group.registrations[len(group.registrations)-1]
+		registration := gen.registrations[last]
+		fmt.Fprintf(w, "case %s[%d]:\ninfo.Lifetime = di.%s\n", typesVar, g, registration.Lifetime)
+		switch registration.Lifetime {
+		case "Singleton":
+			fmt.Fprintf(w, "info.IsInstantiated = container.root.created%d.Load()\n", last)
+		case "Scoped":
+			fmt.Fprintf(w, "info.IsInstantiated = container.created%d.Load()\n", last)
+		}
+	}
+	fmt.Fprintf(w, "}\nreturn info\n}\n")
+}
+
+func (gen *containerGenerator) writeAccessors(w io.Writer, group *serviceGroup) {
+	last := group.registrations[len(group.registrations)-1]
+	fmt.Fprintf(w, `
+// %[2]s resolves the %[3]s service.
+func (container *%[1]s) %[2]s() (service %[3]s, err error) {
+	if container.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+	return container.get%[4]d()
+}
+
+// all%[2]s resolves every %[3]s service.
+func (container *%[1]s) all%[2]s() ([]%[3]s, error) {
+	getters := []func() (%[3]s, error){
+`, gen.name, group.accessor, group.expression, last)
+	for _, i := range group.registrations {
+		fmt.Fprintf(w, "container.get%d,\n", i)
+	}
+	fmt.Fprintf(w, `}
+	services := make([]%s, len(getters))
+	for i, get := range getters {
+		service, err := get()
+		if err != nil {
+			return nil, err
+		}
+		services[i] = service
+	}
+	return services, nil
+}
+`, group.expression)
+}
+
+// writeGet writes the method resolving a registration from the container
+// owning its lifetime.
+func (gen *containerGenerator) writeGet(w io.Writer, i int) {
+	registration := gen.registrations[i]
+	fmt.Fprintf(w, "\n// get%d resolves the %s %s registered at %s:%d.\n",
+		i, strings.ToLower(registration.Lifetime), registration.ServiceType,
+		filepath.Base(registration.Position.Filename), registration.Position.Line)
+	fmt.Fprintf(w, "func (container *%s) get%d() (service %s, err error) {\n", gen.name, i, registration.ServiceType)
+
+	switch registration.Lifetime {
+	case "Transient":
+		fmt.Fprintf(w, "return container.create%d()\n}\n", i)
+		return
+	case "Singleton":
+		fmt.Fprintf(w, "owner := container.root\n")
+	case "Scoped":
+		fmt.Fprintf(w, "if !container.IsScoped() {\nerr = di.ErrScopedServiceFromRoot\nreturn\n}\nowner := container\n")
+	}
+	fmt.Fprintf(w, `if owner.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+
+	owner.mutex%[1]d.Lock()
+	defer owner.mutex%[1]d.Unlock()
+	if !owner.created%[1]d.Load() {
+		if owner.service%[1]d, err = owner.create%[1]d(); err != nil {
+			return
+		}
+		owner.created%[1]d.Store(true)
+	}
+	return owner.service%[1]d, nil
+}
+`, i)
+}
+
+// writeCreate writes the method creating an instance of a registration,
+// resolving its requirements from the container owning it.
+func (gen *containerGenerator) writeCreate(w io.Writer, i int) {
+	registration := gen.registrations[i]
+	instanceType := gen.typeString(registration.instanceType)
+	fmt.Fprintf(w, "\n// create%d creates an instance of %s.\n", i, registration.ServiceType)
+	fmt.Fprintf(w, "func (container *%s) create%d() (instance %s, err error) {\n", gen.name, i, instanceType)
+
+	args := make([]string, len(registration.requirements))
+	for j, requirement := range registration.requirements {
+		args[j] = fmt.Sprintf("a%d", j)
+		dependency := gen.dependencies[i][j]
+		switch dependency.kind {
+		case dependencyService:
+			fmt.Fprintf(w, "a%d, err := container.get%d()\nif err != nil {\nreturn\n}\n", j, dependency.required()[0])
+		case dependencySlice:
+			fmt.Fprintf(w, "a%d, err := container.all%s()\nif err != nil {\nreturn\n}\n", j, dependency.group.accessor)
+		case dependencyContainer:
+			fmt.Fprintf(w, "var a%d di.ServiceContainer = container\n", j)
+		case dependencyEmptySlice:
+			fmt.Fprintf(w, "a%d := make(%s, 0)\n", j, gen.typeString(requirement))
+		}
+	}
+
+	switch {
+	case registration.Struct != "":
+		fields := make([]string, len(args))
+		for j, arg := range args {
+			fields[j] = registration.fields[j] + ": " + arg
+		}
+		fmt.Fprintf(w, "created := &%s{%s}\n", registration.Struct, strings.Join(fields, ", "))
+	case registration.returnsError:
+		fmt.Fprintf(w, "created, err := %s(%s)\nif err != nil {\nreturn\n}\n", registration.Func, strings.Join(args, ", "))
+	default:
+		fmt.Fprintf(w, "created := %s(%s)\n", registration.Func, strings.Join(args, ", "))
+	}
+
+	if isNillable(registration.instanceType) && registration.Struct == "" {
+		fmt.Fprintf(w, "if created != nil {\nif err = container.track(created); err != nil {\nreturn\n}\n}\n")
+	} else {
+		fmt.Fprintf(w, "if err = container.track(created); err != nil {\nreturn\n}\n")
+	}
+	fmt.Fprintf(w, "return created, nil\n}\n")
+}
+
+// isNillable tells whether values of a type can be nil, in which case they
+// are not tracked for disposal.
+func isNillable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Map, *types.Slice, *types.Chan, *types.Signature:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/go-mike/di"
+	"github.com/go-mike/di/cmd/di-gen/testdata/example"
+	"github.com/stretchr/testify/assert"
+)
+
+// testContainers runs a test against a container built from the generated
+// registrations and against the compiled container, which must behave the same.
+func testContainers(t *testing.T, test func(t *testing.T, container di.ServiceContainer)) {
+	t.Run("reflective", func(t *testing.T) {
+		services := di.NewServiceCollection()
+		assert.NoError(t, example.RegisterGenerated(services))
+		container, err := services.Build()
+		assert.NoError(t, err)
+		defer container.Dispose()
+		test(t, container)
+	})
+	t.Run("compiled", func(t *testing.T) {
+		container := example.NewAppContainer()
+		defer container.Dispose()
+		test(t, container)
+	})
+}
+
+func TestContainer_Singletons(t *testing.T) {
+	testContainers(t, func(t *testing.T, container di.ServiceContainer) {
+		repo, err := di.GetService[example.Repo](container.Provider())
+		assert.NoError(t, err)
+		assert.Equal(t, "memory", repo.Find(1))
+
+		scope, err := container.CreateScope()
+		assert.NoError(t, err)
+		defer scope.Dispose()
+		fromScope, err := di.GetService[example.Repo](scope.Provider())
+		assert.NoError(t, err)
+		assert.Same(t, repo, fromScope)
+
+		// Registrations of a same constructor are distinct singletons
+		sqlRepo, err := di.GetService[*example.SQLRepo](container.Provider())
+		assert.NoError(t, err)
+		assert.NotSame(t, repo, sqlRepo)
+
+		repos, err := di.GetService[[]example.Repo](container.Provider())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cache", "memory"}, []string{repos[0].Find(1), repos[1].Find(1)})
+		assert.Same(t, repo, repos[1])
+	})
+}
+
+func TestContainer_Scopes(t *testing.T) {
+	testContainers(t, func(t *testing.T, container di.ServiceContainer) {
+		_, err := di.GetService[*example.Handler](container.Provider())
+		assert.ErrorIs(t, err, di.ErrScopedServiceFromRoot)
+
+		scope, err := container.CreateScope()
+		assert.NoError(t, err)
+		assert.True(t, scope.IsScoped())
+		assert.False(t, container.IsScoped())
+		handler, err := di.GetService[*example.Handler](scope.Provider())
+		assert.NoError(t, err)
+		again, err := di.GetService[*example.Handler](scope.Provider())
+		assert.NoError(t, err)
+		assert.Same(t, handler, again)
+
+		other, err := container.CreateScope()
+		assert.NoError(t, err)
+		fromOther, err := di.GetService[*example.Handler](other.Provider())
+		assert.NoError(t, err)
+		assert.NotSame(t, handler, fromOther)
+
+		session, err := di.GetService[*example.Session](scope.Provider())
+		assert.NoError(t, err)
+		scope.Dispose()
+		assert.True(t, session.Disposed)
+		assert.True(t, scope.IsDisposed())
+		_, err = di.GetService[*example.Handler](scope.Provider())
+		assert.ErrorIs(t, err, di.ErrServiceContainerDisposed)
+		_, err = scope.CreateScope()
+		assert.ErrorIs(t, err, di.ErrServiceContainerDisposed)
+	})
+}
+
+func TestContainer_Transients(t *testing.T) {
+	testContainers(t, func(t *testing.T, container di.ServiceContainer) {
+		scope, err := container.CreateScope()
+		assert.NoError(t, err)
+		defer scope.Dispose()
+
+		audit, err := di.GetService[*example.Audit](scope.Provider())
+		assert.NoError(t, err)
+		again, err := di.GetService[*example.Audit](scope.Provider())
+		assert.NoError(t, err)
+		assert.NotSame(t, audit, again)
+		assert.Same(t, audit.Session, again.Session)
+		assert.Equal(t, []io.Writer{io.Discard}, audit.Writers)
+		assert.Len(t, audit.Repos, 2)
+
+		// The built-in container service is the scope creating the service
+		assert.Same(t, scope, audit.Scope)
+		resolved, err := di.GetService[di.ServiceContainer](container.Provider())
+		assert.NoError(t, err)
+		assert.Same(t, container, resolved)
+	})
+}
+
+func TestContainer_Errors(t *testing.T) {
+	testContainers(t, func(t *testing.T, container di.ServiceContainer) {
+		_, err := di.GetService[*example.Remote](container.Provider())
+		assert.ErrorIs(t, err, example.ErrUnavailable)
+
+		_, err = di.GetService[*example.Unregistered](container.Provider())
+		assert.ErrorIs(t, err, di.ErrServiceNotFound)
+		unregistered, err := di.GetService[[]*example.Unregistered](container.Provider())
+		assert.NoError(t, err)
+		assert.NotNil(t, unregistered)
+		assert.Empty(t, unregistered)
+
+		container.Dispose()
+		_, err = di.GetService[example.Repo](container.Provider())
+		assert.ErrorIs(t, err, di.ErrServiceContainerDisposed)
+		_, err = container.CreateScope()
+		assert.ErrorIs(t, err, di.ErrServiceContainerDisposed)
+	})
+}
+
+func TestContainer_ServiceInfo(t *testing.T) {
+	testContainers(t, func(t *testing.T, container di.ServiceContainer) {
+		info := serviceInfo[example.Repo](container.Provider())
+		assert.Equal(t, di.Singleton, info.Lifetime)
+		assert.False(t, info.IsInstantiated)
+		_, err := di.GetService[example.Repo](container.Provider())
+		assert.NoError(t, err)
+		assert.True(t, serviceInfo[example.Repo](container.Provider()).IsInstantiated)
+
+		assert.Equal(t, di.Scoped, serviceInfo[*example.Handler](container.Provider()).Lifetime)
+		assert.Equal(t, di.Transient, serviceInfo[*example.Audit](container.Provider()).Lifetime)
+		assert.True(t, serviceInfo[*example.Unregistered](container.Provider()).IsNotFound())
+	})
+}
+
+func BenchmarkContainer_Transient(b *testing.B) {
+	services := di.NewServiceCollection()
+	assert.NoError(b, example.RegisterGenerated(services))
+	reflective, err := services.Build()
+	assert.NoError(b, err)
+	defer reflective.Dispose()
+	compiled := example.NewAppContainer()
+	defer compiled.Dispose()
+
+	for name, container := range map[string]di.ServiceContainer{"reflective": reflective, "compiled": compiled} {
+		b.Run(name, func(b *testing.B) {
+			scope, err := container.CreateScope()
+			assert.NoError(b, err)
+			defer scope.Dispose()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := di.GetService[*example.Audit](scope.Provider()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func serviceInfo[T any](provider di.ServiceProvider) di.ServiceInfo {
+	return provider.GetServiceInfo(reflect.TypeOf((*T)(nil)).Elem())
+}
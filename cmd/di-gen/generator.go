@@ -0,0 +1,406 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// diImportPath is the import path of the di package in generated files.
+const diImportPath = "github.com/go-mike/di"
+
+// loadMode is the information needed from the loaded packages.
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+	packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedTypesInfo
+
+var errInvalidTarget = errors.New("invalid annotated declaration")
+
+// registration is an annotated constructor or struct.
+type registration struct {
+	annotation
+	// Position is the location of the annotated declaration.
+	Position token.Position
+	// Func is the name of the constructor, when a function is annotated.
+	Func string
+	// Struct is the name of the struct type, when a struct is annotated.
+	Struct string
+	// ServiceType is the Go expression of the registered service type.
+	ServiceType string
+
+	// serviceType and instanceType are the registered and created types.
+	serviceType  types.Type
+	instanceType types.Type
+	// requirements are the types of the parameters of the constructor, or
+	// of the fields of the struct.
+	requirements []types.Type
+	// fields are the names of the fields of the struct.
+	fields []string
+	// returnsError tells whether the constructor also returns an error.
+	returnsError bool
+}
+
+// generator collects the registrations of a package and writes its file.
+type generator struct {
+	pkg           *packages.Package
+	registrations []registration
+	// imports maps the paths of the packages used by the service types to
+	// their names in the generated file.
+	imports map[string]string
+	// importNames maps the same paths to the names of their packages.
+	importNames map[string]string
+	// std holds the standard library packages used by the generated code.
+	std map[string]bool
+}
+
+func newGenerator(pkg *packages.Package) *generator {
+	return &generator{
+		pkg:         pkg,
+		imports:     map[string]string{},
+		importNames: map[string]string{},
+		std:         map[string]bool{},
+	}
+}
+
+// collect finds the annotated declarations of the package, in file order.
+func (gen *generator) collect() error {
+	files := sortedFiles(gen.pkg)
+	var errs []error
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				errs = append(errs, gen.collectFunc(decl))
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					doc := spec.(*ast.TypeSpec).Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+					errs = append(errs, gen.collectStruct(spec.(*ast.TypeSpec), doc))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sortedFiles returns the syntax of the package sorted by file name.
+func sortedFiles(pkg *packages.Package) []*ast.File {
+	files := append([]*ast.File(nil), pkg.Syntax...)
+	sort.SliceStable(files, func(i, j int) bool {
+		return pkg.Fset.File(files[i].Pos()).Name() < pkg.Fset.File(files[j].Pos()).Name()
+	})
+	return files
+}
+
+func (gen *generator) annotations(doc *ast.CommentGroup) ([]annotation, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	var result []annotation
+	for _, comment := range doc.List {
+		parsed, ok, err := parseAnnotation(comment.Text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", gen.pkg.Fset.Position(comment.Pos()), err)
+		}
+		if ok {
+			result = append(result, parsed)
+		}
+	}
+	return result, nil
+}
+
+func (gen *generator) collectFunc(decl *ast.FuncDecl) error {
+	annotations, err := gen.annotations(decl.Doc)
+	if err != nil || len(annotations) == 0 {
+		return err
+	}
+
+	position := gen.pkg.Fset.Position(decl.Pos())
+	if decl.Recv != nil || decl.Type.TypeParams != nil {
+		return fmt.Errorf("%s: %w: %s must be a function without receiver or type parameters", position, errInvalidTarget, decl.Name.Name)
+	}
+	function, ok := gen.pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return fmt.Errorf("%s: %w: %s has no type information", position, errInvalidTarget, decl.Name.Name)
+	}
+	results := function.Type().(*types.Signature).Results()
+	if results.Len() < 1 || results.Len() > 2 ||
+		results.Len() == 2 && !types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type()) {
+		return fmt.Errorf("%s: %w: %s must return a service, and optionally an error", position, errInvalidTarget, decl.Name.Name)
+	}
+
+	params := function.Type().(*types.Signature).Params()
+	requirements := make([]types.Type, params.Len())
+	for i := range requirements {
+		requirements[i] = params.At(i).Type()
+	}
+
+	for _, annotation := range annotations {
+		instanceType := results.At(0).Type()
+		expression, serviceType, err := gen.serviceType(position, annotation, instanceType)
+		if err != nil {
+			return err
+		}
+		gen.registrations = append(gen.registrations, registration{
+			annotation:   annotation,
+			Position:     position,
+			Func:         decl.Name.Name,
+			ServiceType:  expression,
+			serviceType:  serviceType,
+			instanceType: instanceType,
+			requirements: requirements,
+			returnsError: results.Len() == 2,
+		})
+	}
+	return nil
+}
+
+func (gen *generator) collectStruct(spec *ast.TypeSpec, doc *ast.CommentGroup) error {
+	annotations, err := gen.annotations(doc)
+	if err != nil || len(annotations) == 0 {
+		return err
+	}
+
+	position := gen.pkg.Fset.Position(spec.Pos())
+	if _, ok := spec.Type.(*ast.StructType); !ok || spec.TypeParams != nil || spec.Assign.IsValid() {
+		return fmt.Errorf("%s: %w: %s must be a struct type without type parameters", position, errInvalidTarget, spec.Name.Name)
+	}
+	typeName, ok := gen.pkg.TypesInfo.Defs[spec.Name].(*types.TypeName)
+	if !ok {
+		return fmt.Errorf("%s: %w: %s has no type information", position, errInvalidTarget, spec.Name.Name)
+	}
+
+	structType := typeName.Type().Underlying().(*types.Struct)
+	requirements := make([]types.Type, structType.NumFields())
+	fields := make([]string, structType.NumFields())
+	for i := range requirements {
+		requirements[i] = structType.Field(i).Type()
+		fields[i] = structType.Field(i).Name()
+	}
+
+	for _, annotation := range annotations {
+		// Struct factories create pointers to the struct
+		instanceType := types.NewPointer(typeName.Type())
+		expression, serviceType, err := gen.serviceType(position, annotation, instanceType)
+		if err != nil {
+			return err
+		}
+		gen.registrations = append(gen.registrations, registration{
+			annotation:   annotation,
+			Position:     position,
+			Struct:       spec.Name.Name,
+			ServiceType:  expression,
+			serviceType:  serviceType,
+			instanceType: instanceType,
+			requirements: requirements,
+			fields:       fields,
+		})
+	}
+	return nil
+}
+
+// serviceType returns the expression and the type of the service type of an
+// annotation, checking that instances of the given type can be registered as it.
+func (gen *generator) serviceType(
+	position token.Position,
+	annotation annotation,
+	instanceType types.Type,
+) (string, types.Type, error) {
+	if annotation.As == "" {
+		expression := gen.typeString(instanceType)
+		if strings.Contains(expression, "invalid type") {
+			return "", nil, fmt.Errorf("%s: %w: service type %s is not valid", position, errInvalidTarget, expression)
+		}
+		return expression, instanceType, nil
+	}
+
+	asType, ok := gen.pkg.Types.Scope().Lookup(annotation.As).(*types.TypeName)
+	if !ok {
+		return "", nil, fmt.Errorf(
+			"%s: %w: as=%s is not a type of package %s",
+			position, errInvalidAnnotation, annotation.As, gen.pkg.Name)
+	}
+	if !types.AssignableTo(instanceType, asType.Type()) {
+		return "", nil, fmt.Errorf(
+			"%s: %w: %s cannot be registered as %s",
+			position, errInvalidAnnotation, gen.typeString(instanceType), annotation.As)
+	}
+	return annotation.As, asType.Type(), nil
+}
+
+// typeString returns the expression of a type in the generated file.
+func (gen *generator) typeString(t types.Type) string {
+	return types.TypeString(t, gen.qualifier)
+}
+
+// qualifier names the packages used by the service types, recording their imports.
+func (gen *generator) qualifier(pkg *types.Package) string {
+	if pkg.Path() == gen.pkg.PkgPath {
+		return ""
+	}
+	if name, ok := gen.imports[pkg.Path()]; ok {
+		return name
+	}
+	name := pkg.Name()
+	for i := 2; gen.importNameUsed(name); i++ {
+		name = pkg.Name() + strconv.Itoa(i)
+	}
+	gen.imports[pkg.Path()] = name
+	gen.importNames[pkg.Path()] = pkg.Name()
+	return name
+}
+
+func (gen *generator) importNameUsed(name string) bool {
+	switch name {
+	case "di", "fmt", "reflect", "sync", "atomic":
+		return true
+	}
+	for _, used := range gen.imports {
+		if used == name {
+			return true
+		}
+	}
+	return false
+}
+
+// generate returns the formatted source of the file registering the
+// collected services with a function of the given name, and declaring a
+// compiled container of them when a container name is given.
+func (gen *generator) generate(funcName string, containerName string) ([]byte, error) {
+	var container *containerGenerator
+	if containerName != "" {
+		var err error
+		if container, err = newContainerGenerator(gen, containerName); err != nil {
+			return nil, err
+		}
+	}
+
+	// The body is written first, recording the imports it needs
+	var body bytes.Buffer
+	gen.writeRegister(&body, funcName)
+	if container != nil {
+		container.write(&body)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by di-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", gen.pkg.Name)
+	buf.WriteString("import (\n")
+	for _, spec := range gen.importSpecs() {
+		if spec == "" {
+			buf.WriteString("\n")
+		} else {
+			fmt.Fprintf(&buf, "\t%s\n", spec)
+		}
+	}
+	buf.WriteString(")\n")
+	buf.Write(body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+// writeRegister writes the function adding the collected services to a collection.
+func (gen *generator) writeRegister(w io.Writer, funcName string) {
+	fmt.Fprintf(w, "\n// %s adds the services annotated in package %s to the given collection.\n", funcName, gen.pkg.Name)
+	fmt.Fprintf(w, "func %s(services di.ServiceCollection) error {\n", funcName)
+	if len(gen.registrations) > 0 {
+		fmt.Fprintf(w, "var factory di.ServiceFactory\nvar err error\n")
+	}
+	for _, registration := range gen.registrations {
+		fmt.Fprintf(w, "\n// %s:%d\n", filepath.Base(registration.Position.Filename), registration.Position.Line)
+		if registration.Func != "" {
+			fmt.Fprintf(w, "if factory, err = di.NewFuncFactory(%s); err != nil {\n", registration.Func)
+		} else {
+			gen.std["reflect"] = true
+			fmt.Fprintf(w,
+				"if factory, err = di.NewStructFactoryForType(reflect.TypeOf(%s{})); err != nil {\n",
+				registration.Struct)
+		}
+		fmt.Fprintf(w, "return err\n}\n")
+		fmt.Fprintf(w, "services.Add(%s)\n", descriptorExpression(registration))
+	}
+	fmt.Fprintf(w, "\nreturn nil\n}\n")
+}
+
+// importSpecs returns the imports of the generated file, standard library
+// first, with an empty string between the groups.
+func (gen *generator) importSpecs() []string {
+	var std, others []string
+	for path := range gen.std {
+		std = append(std, strconv.Quote(path))
+	}
+	others = append(others, strconv.Quote(diImportPath))
+	for path, name := range gen.imports {
+		spec := strconv.Quote(path)
+		if name != gen.importNames[path] {
+			spec = name + " " + spec
+		}
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			others = append(others, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	byPath := func(specs []string) {
+		sort.Slice(specs, func(i, j int) bool {
+			return specs[i][strings.Index(specs[i], `"`):] < specs[j][strings.Index(specs[j], `"`):]
+		})
+	}
+	byPath(std)
+	byPath(others)
+	if len(std) == 0 {
+		return others
+	}
+	return append(append(std, ""), others...)
+}
+
+// descriptorExpression returns the expression creating the descriptor of a
+// registration, with its options applied.
+func descriptorExpression(registration registration) string {
+	expression := fmt.Sprintf("di.NewDescriptor[%s](di.%s, factory)", registration.ServiceType, registration.Lifetime)
+	if registration.Name != "" {
+		expression = fmt.Sprintf("di.WithName(%s, %q)", expression, registration.Name)
+	}
+	if registration.HasOrder {
+		expression = fmt.Sprintf("di.WithOrder(%s, %d)", expression, registration.Order)
+	}
+	if len(registration.Before) > 0 {
+		expression = fmt.Sprintf("di.WithBefore(%s, %s)", expression, quoteAll(registration.Before))
+	}
+	if len(registration.After) > 0 {
+		expression = fmt.Sprintf("di.WithAfter(%s, %s)", expression, quoteAll(registration.After))
+	}
+	if len(registration.Tags) > 0 {
+		expression = fmt.Sprintf("di.WithTags(%s, %s)", expression, quoteAll(registration.Tags))
+	}
+	for _, entry := range registration.Metadata {
+		expression = fmt.Sprintf("di.WithMetadata(%s, %q, %q)", expression, entry[0], entry[1])
+	}
+	if registration.Eager {
+		expression = fmt.Sprintf("di.Eager(%s)", expression)
+	}
+	return expression
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, ", ")
+}
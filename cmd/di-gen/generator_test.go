@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/packages"
+)
+
+func loadTestPackage(t *testing.T, name string) *packages.Package {
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode}, "./testdata/"+name)
+	assert.NoError(t, err)
+	assert.Len(t, pkgs, 1)
+	assert.NoError(t, checkPackage(pkgs[0]))
+	return pkgs[0]
+}
+
+func TestGenerator_Example(t *testing.T) {
+	pkg := loadTestPackage(t, "example")
+	// The generated file compiles with the package
+	assert.Empty(t, pkg.Errors)
+
+	gen := newGenerator(pkg)
+	assert.NoError(t, gen.collect())
+	assert.Equal(t,
+		[]string{"NewConfig", "NewSQLRepo", "NewSQLRepo", "NewMemoryRepo", "NewLogWriter", "Handler", "NewSession", "Audit", "NewRemote"},
+		mapRegistrations(gen.registrations, func(registration registration) string {
+			return registration.Func + registration.Struct
+		}))
+	assert.Equal(t,
+		[]string{"*Config", "Repo", "*SQLRepo", "Repo", "io.Writer", "*Handler", "*Session", "*Audit", "*Remote"},
+		mapRegistrations(gen.registrations, func(registration registration) string {
+			return registration.ServiceType
+		}))
+
+	source, err := gen.generate("RegisterGenerated", "AppContainer")
+	assert.NoError(t, err)
+	expected, err := os.ReadFile(filepath.Join("testdata", "example", "di_gen.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(source))
+}
+
+func TestGenerator_Invalid(t *testing.T) {
+	gen := newGenerator(loadTestPackage(t, "invalid"))
+
+	err := gen.collect()
+	assert.ErrorIs(t, err, errInvalidAnnotation)
+	assert.ErrorIs(t, err, errInvalidTarget)
+	assert.ErrorContains(t, err, "invalid.go:6:1: invalid annotation: as=Missing is not a type of package invalid")
+	assert.ErrorContains(t, err, "Method must be a function without receiver or type parameters")
+	assert.ErrorContains(t, err, "NewNothing must return a service, and optionally an error")
+	assert.ErrorContains(t, err, `unknown lifetime "forever"`)
+	assert.ErrorContains(t, err, "Alias must be a struct type without type parameters")
+	assert.ErrorContains(t, err, "*Other cannot be registered as Target")
+}
+
+func TestContainerGenerator_Invalid(t *testing.T) {
+	for name, message := range map[string]string{
+		"cycle":         "cycle.go:8:1: invalid compiled container: circular dependency *A => *B => *A",
+		"captive":       "captive.go:18:6: invalid compiled container: singleton *Cache requires a scoped service",
+		"pooled":        "pooled.go:6:1: invalid compiled container: pooled lifetime is not supported",
+		"perresolution": "perresolution.go:6:1: invalid compiled container: perresolution lifetime is not supported",
+	} {
+		gen := newGenerator(loadTestPackage(t, name))
+		assert.NoError(t, gen.collect())
+
+		// The registrations are still generated without a container
+		_, err := gen.generate("RegisterGenerated", "")
+		assert.NoError(t, err, name)
+
+		_, err = gen.generate("RegisterGenerated", "Container")
+		assert.ErrorIs(t, err, errInvalidContainer, name)
+		assert.ErrorContains(t, err, message, name)
+	}
+}
+
+func mapRegistrations(registrations []registration, mapper func(registration) string) []string {
+	result := make([]string, len(registrations))
+	for i, registration := range registrations {
+		result[i] = mapper(registration)
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+// Command di-gen generates the registration of the constructors and structs
+// annotated with //di: comments, so that registration lists follow the code.
+//
+// Annotate a constructor or a struct type with its lifetime and options:
+//
+//	//di:singleton as=Repo name=primary
+//	func NewSQLRepo(db *sql.DB) (*SQLRepo, error) { ... }
+//
+//	//di:scoped tags=http
+//	type Handler struct { Repo Repo }
+//
+// The lifetime is singleton, scoped, transient, pooled or perresolution.
+// The options are:
+//
+//	as=T            register as type T of the package, instead of the result type
+//	                of the constructor or the pointer to the struct
+//	name=N          di.WithName
+//	order=N         di.WithOrder
+//	before=A,B      di.WithBefore
+//	after=A,B       di.WithAfter
+//	tags=A,B        di.WithTags
+//	eager           di.Eager
+//	key=value       di.WithMetadata for any other key
+//
+// Several annotations on a declaration register it several times.
+//
+// Usage:
+//
+//	//go:generate go run github.com/go-mike/di/cmd/di-gen [flags] [packages]
+//
+// For each package, default ".", di-gen writes a file declaring a function
+// which adds the annotated services to a di.ServiceCollection:
+//
+//	func RegisterGenerated(services di.ServiceCollection) error
+//
+// With -container=Name, the file also declares a compiled container type,
+// implementing di.ServiceContainer and di.ServiceProvider without reflection:
+// services are created by plain calls to their constructors, cached in typed
+// fields, and resolved by typed accessors named after their service types.
+//
+//	func NewName() *Name
+//	func (container *Name) Repo() (Repo, error)
+//
+// Compiled containers resolve the same services as containers built from the
+// generated function, but ignore metadata, hooks and build options, and do
+// not support the before and after options. Missing services, circular
+// dependencies and singletons requiring scoped services fail the generation.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	output := flag.String("output", "di_gen.go", "name of the generated file in each package directory")
+	funcName := flag.String("func", "RegisterGenerated", "name of the generated function")
+	container := flag.String("container", "", "name of a compiled container type to generate, none by default")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: di-gen [flags] [packages]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(flag.Args(), options{output: *output, funcName: *funcName, container: *container}); err != nil {
+		fmt.Fprintln(os.Stderr, "di-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// options are the command line flags.
+type options struct {
+	output    string
+	funcName  string
+	container string
+}
+
+func run(patterns []string, options options) error {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode}, patterns...)
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range pkgs {
+		if err := checkPackage(pkg); err != nil {
+			return err
+		}
+		gen := newGenerator(pkg)
+		if err := gen.collect(); err != nil {
+			return err
+		}
+		if len(gen.registrations) == 0 {
+			continue
+		}
+		source, err := gen.generate(options.funcName, options.container)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pkg.PkgPath, err)
+		}
+		if err := os.WriteFile(filepath.Join(filepath.Dir(pkg.GoFiles[0]), options.output), source, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPackage fails on packages which could not be listed or parsed. Type
+// errors are tolerated, since the code may call a function which is not
+// generated yet.
+func checkPackage(pkg *packages.Package) error {
+	for _, err := range pkg.Errors {
+		if err.Kind != packages.TypeError {
+			return fmt.Errorf("%s: %w", pkg.PkgPath, err)
+		}
+	}
+	if len(pkg.GoFiles) == 0 {
+		return fmt.Errorf("%s: no Go files", pkg.PkgPath)
+	}
+	return nil
+}
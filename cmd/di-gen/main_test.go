@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-mike/di"
+	"github.com/go-mike/di/cmd/di-gen/testdata/example"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	output := filepath.Join("testdata", "example", "di_gen_run.go")
+	t.Cleanup(func() { os.Remove(output) })
+
+	assert.NoError(t, run([]string{"./testdata/example"}, options{output: "di_gen_run.go", funcName: "RegisterRun"}))
+	source, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Contains(t, string(source), "func RegisterRun(services di.ServiceCollection) error {")
+
+	assert.Error(t, run([]string{"./testdata/invalid"}, options{output: "di_gen_run.go", funcName: "RegisterRun"}))
+	_, err = os.Stat(filepath.Join("testdata", "invalid", "di_gen_run.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRegisterGenerated(t *testing.T) {
+	services := di.NewServiceCollection()
+	assert.NoError(t, example.RegisterGenerated(services))
+	container, err := services.Build()
+	assert.NoError(t, err)
+	defer container.Dispose()
+
+	repo, err := di.GetService[example.Repo](container.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, "memory", repo.Find(1))
+	primary := services.FindDescriptors(func(descriptor di.ServiceDescriptor) bool {
+		name, _ := di.GetMetadata[string](descriptor, di.NameMetadataKey)
+		return name == "primary"
+	})
+	assert.Len(t, primary, 1)
+	assert.Equal(t, di.Singleton, primary[0].Lifetime())
+
+	writers, err := di.GetServicesWithTag[io.Writer](container.Provider(), "logging")
+	assert.NoError(t, err)
+	assert.Equal(t, []io.Writer{io.Discard}, writers)
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+	handler, err := di.GetService[*example.Handler](scope.Provider())
+	assert.NoError(t, err)
+	assert.Same(t, repo, handler.Repo)
+}
@@ -0,0 +1,20 @@
+package captive
+
+type Session struct{}
+
+//di:scoped
+func NewSession() *Session {
+	return &Session{}
+}
+
+// Request is transient, so singletons requiring it capture its session.
+//
+//di:transient
+type Request struct {
+	Session *Session
+}
+
+//di:singleton
+type Cache struct {
+	Request *Request
+}
@@ -0,0 +1,15 @@
+package cycle
+
+type A struct{ B *B }
+
+type B struct{ A *A }
+
+//di:singleton
+func NewA(b *B) *A {
+	return &A{B: b}
+}
+
+//di:transient
+func NewB(a *A) *B {
+	return &B{A: a}
+}
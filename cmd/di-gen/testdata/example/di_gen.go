@@ -0,0 +1,779 @@
+// Code generated by di-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-mike/di"
+)
+
+// RegisterGenerated adds the services annotated in package example to the given collection.
+func RegisterGenerated(services di.ServiceCollection) error {
+	var factory di.ServiceFactory
+	var err error
+
+	// example.go:17
+	if factory, err = di.NewFuncFactory(NewConfig); err != nil {
+		return err
+	}
+	services.Add(di.NewDescriptor[*Config](di.Singleton, factory))
+
+	// example.go:37
+	if factory, err = di.NewFuncFactory(NewSQLRepo); err != nil {
+		return err
+	}
+	services.Add(di.WithOrder(di.WithName(di.NewDescriptor[Repo](di.Singleton, factory), "primary"), 1))
+
+	// example.go:37
+	if factory, err = di.NewFuncFactory(NewSQLRepo); err != nil {
+		return err
+	}
+	services.Add(di.NewDescriptor[*SQLRepo](di.Singleton, factory))
+
+	// example.go:50
+	if factory, err = di.NewFuncFactory(NewMemoryRepo); err != nil {
+		return err
+	}
+	services.Add(di.WithOrder(di.NewDescriptor[Repo](di.Singleton, factory), -1))
+
+	// example.go:55
+	if factory, err = di.NewFuncFactory(NewLogWriter); err != nil {
+		return err
+	}
+	services.Add(di.WithMetadata(di.WithTags(di.NewDescriptor[io.Writer](di.Transient, factory), "io", "logging"), "key", "log"))
+
+	// example.go:62
+	if factory, err = di.NewStructFactoryForType(reflect.TypeOf(Handler{})); err != nil {
+		return err
+	}
+	services.Add(di.NewDescriptor[*Handler](di.Scoped, factory))
+
+	// example.go:76
+	if factory, err = di.NewFuncFactory(NewSession); err != nil {
+		return err
+	}
+	services.Add(di.NewDescriptor[*Session](di.Scoped, factory))
+
+	// example.go:83
+	if factory, err = di.NewStructFactoryForType(reflect.TypeOf(Audit{})); err != nil {
+		return err
+	}
+	services.Add(di.NewDescriptor[*Audit](di.Transient, factory))
+
+	// example.go:95
+	if factory, err = di.NewFuncFactory(NewRemote); err != nil {
+		return err
+	}
+	services.Add(di.NewDescriptor[*Remote](di.Transient, factory))
+
+	return nil
+}
+
+// AppContainer is a compiled service container of the services annotated in
+// package example, resolving them without reflection. It resolves the same
+// services as a container built from the generated registrations, without
+// their metadata, hooks and build options.
+type AppContainer struct {
+	root        *AppContainer
+	mutex       sync.Mutex
+	disposed    bool
+	disposables []di.Disposable
+
+	service0 *Config
+	created0 atomic.Bool
+	mutex0   sync.Mutex
+
+	service1 Repo
+	created1 atomic.Bool
+	mutex1   sync.Mutex
+
+	service2 *SQLRepo
+	created2 atomic.Bool
+	mutex2   sync.Mutex
+
+	service3 Repo
+	created3 atomic.Bool
+	mutex3   sync.Mutex
+
+	service5 *Handler
+	created5 atomic.Bool
+	mutex5   sync.Mutex
+
+	service6 *Session
+	created6 atomic.Bool
+	mutex6   sync.Mutex
+}
+
+var _ di.ServiceContainer = (*AppContainer)(nil)
+var _ di.ServiceProvider = (*AppContainer)(nil)
+
+// appContainerTypes are the service types of AppContainer, and appContainerSliceTypes the slices of them.
+var (
+	appContainerTypes = [...]reflect.Type{
+		reflect.TypeOf((**Config)(nil)).Elem(),
+		reflect.TypeOf((*Repo)(nil)).Elem(),
+		reflect.TypeOf((**SQLRepo)(nil)).Elem(),
+		reflect.TypeOf((*io.Writer)(nil)).Elem(),
+		reflect.TypeOf((**Handler)(nil)).Elem(),
+		reflect.TypeOf((**Session)(nil)).Elem(),
+		reflect.TypeOf((**Audit)(nil)).Elem(),
+		reflect.TypeOf((**Remote)(nil)).Elem(),
+	}
+	appContainerSliceTypes = [...]reflect.Type{
+		reflect.TypeOf((*[]*Config)(nil)).Elem(),
+		reflect.TypeOf((*[]Repo)(nil)).Elem(),
+		reflect.TypeOf((*[]*SQLRepo)(nil)).Elem(),
+		reflect.TypeOf((*[]io.Writer)(nil)).Elem(),
+		reflect.TypeOf((*[]*Handler)(nil)).Elem(),
+		reflect.TypeOf((*[]*Session)(nil)).Elem(),
+		reflect.TypeOf((*[]*Audit)(nil)).Elem(),
+		reflect.TypeOf((*[]*Remote)(nil)).Elem(),
+	}
+	appContainerServiceContainerType = reflect.TypeOf((*di.ServiceContainer)(nil)).Elem()
+)
+
+// NewAppContainer creates a root AppContainer.
+func NewAppContainer() *AppContainer {
+	container := &AppContainer{}
+	container.root = container
+	return container
+}
+
+// Provider implements di.ServiceContainer
+func (container *AppContainer) Provider() di.ServiceProvider {
+	return container
+}
+
+// IsScoped implements di.ServiceContainer
+func (container *AppContainer) IsScoped() bool {
+	return container.root != container
+}
+
+// CreateScope implements di.ServiceContainer
+func (container *AppContainer) CreateScope() (di.ServiceContainer, error) {
+	if container.IsDisposed() {
+		return nil, di.ErrServiceContainerDisposed
+	}
+	return &AppContainer{root: container.root}, nil
+}
+
+// CreateScopeWith implements di.ServiceContainer. Compiled containers declare
+// no scope-supplied service types, so no value can be supplied.
+func (container *AppContainer) CreateScopeWith(values ...di.ScopeValue) (di.ServiceContainer, error) {
+	if len(values) > 0 {
+		return nil, fmt.Errorf("%w: %v", di.ErrScopeValueNotDeclared, values[0].ServiceType)
+	}
+	return container.CreateScope()
+}
+
+// Dispose implements di.ServiceContainer.
+// Instances are disposed in reverse creation order, and a panicking instance
+// does not prevent the remaining ones from being disposed.
+func (container *AppContainer) Dispose() {
+	container.mutex.Lock()
+	if container.disposed {
+		container.mutex.Unlock()
+		return
+	}
+	container.disposed = true
+	disposables := container.disposables
+	container.disposables = nil
+	container.mutex.Unlock()
+
+	for i := len(disposables) - 1; i >= 0; i-- {
+		container.dispose(disposables[i])
+	}
+}
+
+// IsDisposed implements di.ServiceContainer
+func (container *AppContainer) IsDisposed() bool {
+	container.mutex.Lock()
+	defer container.mutex.Unlock()
+	return container.disposed
+}
+
+// track registers a disposable instance to be disposed with the container.
+// When the container is already disposed, the instance is disposed right away.
+func (container *AppContainer) track(instance any) error {
+	disposable, ok := instance.(di.Disposable)
+	if !ok {
+		return nil
+	}
+
+	container.mutex.Lock()
+	if container.disposed {
+		container.mutex.Unlock()
+		container.dispose(disposable)
+		return di.ErrServiceContainerDisposed
+	}
+	container.disposables = append(container.disposables, disposable)
+	container.mutex.Unlock()
+	return nil
+}
+
+func (container *AppContainer) dispose(disposable di.Disposable) {
+	defer func() {
+		_ = recover()
+	}()
+	disposable.Dispose()
+}
+
+// GetService implements di.ServiceProvider
+func (container *AppContainer) GetService(serviceType reflect.Type) (any, error) {
+	if container.IsDisposed() {
+		return nil, di.ErrServiceContainerDisposed
+	}
+
+	var service any
+	var err error
+	switch serviceType {
+	case appContainerTypes[0]:
+		service, err = container.get0()
+	case appContainerSliceTypes[0]:
+		service, err = container.allConfig()
+	case appContainerTypes[1]:
+		service, err = container.get1()
+	case appContainerSliceTypes[1]:
+		service, err = container.allRepo()
+	case appContainerTypes[2]:
+		service, err = container.get2()
+	case appContainerSliceTypes[2]:
+		service, err = container.allSQLRepo()
+	case appContainerTypes[3]:
+		service, err = container.get4()
+	case appContainerSliceTypes[3]:
+		service, err = container.allWriter()
+	case appContainerTypes[4]:
+		service, err = container.get5()
+	case appContainerSliceTypes[4]:
+		service, err = container.allHandler()
+	case appContainerTypes[5]:
+		service, err = container.get6()
+	case appContainerSliceTypes[5]:
+		service, err = container.allSession()
+	case appContainerTypes[6]:
+		service, err = container.get7()
+	case appContainerSliceTypes[6]:
+		service, err = container.allAudit()
+	case appContainerTypes[7]:
+		service, err = container.get8()
+	case appContainerSliceTypes[7]:
+		service, err = container.allRemote()
+	case appContainerServiceContainerType:
+		return container, nil
+	default:
+		if serviceType != nil && serviceType.Kind() == reflect.Slice {
+			return reflect.MakeSlice(serviceType, 0, 0).Interface(), nil
+		}
+		return nil, fmt.Errorf("%w: %v", di.ErrServiceNotFound, serviceType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// GetServiceInfo implements di.ServiceProvider
+func (container *AppContainer) GetServiceInfo(serviceType reflect.Type) di.ServiceInfo {
+	info := di.ServiceInfo{ServiceType: serviceType}
+	if container.IsDisposed() {
+		return info
+	}
+
+	switch serviceType {
+	case appContainerTypes[0]:
+		info.Lifetime = di.Singleton
+		info.IsInstantiated = container.root.created0.Load()
+	case appContainerTypes[1]:
+		info.Lifetime = di.Singleton
+		info.IsInstantiated = container.root.created1.Load()
+	case appContainerTypes[2]:
+		info.Lifetime = di.Singleton
+		info.IsInstantiated = container.root.created2.Load()
+	case appContainerTypes[3]:
+		info.Lifetime = di.Transient
+	case appContainerTypes[4]:
+		info.Lifetime = di.Scoped
+		info.IsInstantiated = container.created5.Load()
+	case appContainerTypes[5]:
+		info.Lifetime = di.Scoped
+		info.IsInstantiated = container.created6.Load()
+	case appContainerTypes[6]:
+		info.Lifetime = di.Transient
+	case appContainerTypes[7]:
+		info.Lifetime = di.Transient
+	}
+	return info
+}
+
+// Config resolves the *Config service.
+func (container *AppContainer) Config() (service *Config, err error) {
+	if container.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+	return container.get0()
+}
+
+// allConfig resolves every *Config service.
+func (container *AppContainer) allConfig() ([]*Config, error) {
+	getters := []func() (*Config, error){
+		container.get0,
+	}
+	services := make([]*Config, len(getters))
+	for i, get := range getters {
+		service, err := get()
+		if err != nil {
+			return nil, err
+		}
+		services[i] = service
+	}
+	return services, nil
+}
+
+// Repo resolves the Repo service.
+func (container *AppContainer) Repo() (service Repo, err error) {
+	if container.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+	return container.get1()
+}
+
+// allRepo resolves every Repo service.
+func (container *AppContainer) allRepo() ([]Repo, error) {
+	getters := []func() (Repo, error){
+		container.get3,
+		container.get1,
+	}
+	services := make([]Repo, len(getters))
+	for i, get := range getters {
+		service, err := get()
+		if err != nil {
+			return nil, err
+		}
+		services[i] = service
+	}
+	return services, nil
+}
+
+// SQLRepo resolves the *SQLRepo service.
+func (container *AppContainer) SQLRepo() (service *SQLRepo, err error) {
+	if container.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+	return container.get2()
+}
+
+// allSQLRepo resolves every *SQLRepo service.
+func (container *AppContainer) allSQLRepo() ([]*SQLRepo, error) {
+	getters := []func() (*SQLRepo, error){
+		container.get2,
+	}
+	services := make([]*SQLRepo, len(getters))
+	for i, get := range getters {
+		service, err := get()
+		if err != nil {
+			return nil, err
+		}
+		services[i] = service
+	}
+	return services, nil
+}
+
+// Writer resolves the io.Writer service.
+func (container *AppContainer) Writer() (service io.Writer, err error) {
+	if container.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+	return container.get4()
+}
+
+// allWriter resolves every io.Writer service.
+func (container *AppContainer) allWriter() ([]io.Writer, error) {
+	getters := []func() (io.Writer, error){
+		container.get4,
+	}
+	services := make([]io.Writer, len(getters))
+	for i, get := range getters {
+		service, err := get()
+		if err != nil {
+			return nil, err
+		}
+		services[i] = service
+	}
+	return services, nil
+}
+
+// Handler resolves the *Handler service.
+func (container *AppContainer) Handler() (service *Handler, err error) {
+	if container.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+	return container.get5()
+}
+
+// allHandler resolves every *Handler service.
+func (container *AppContainer) allHandler() ([]*Handler, error) {
+	getters := []func() (*Handler, error){
+		container.get5,
+	}
+	services := make([]*Handler, len(getters))
+	for i, get := range getters {
+		service, err := get()
+		if err != nil {
+			return nil, err
+		}
+		services[i] = service
+	}
+	return services, nil
+}
+
+// Session resolves the *Session service.
+func (container *AppContainer) Session() (service *Session, err error) {
+	if container.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+	return container.get6()
+}
+
+// allSession resolves every *Session service.
+func (container *AppContainer) allSession() ([]*Session, error) {
+	getters := []func() (*Session, error){
+		container.get6,
+	}
+	services := make([]*Session, len(getters))
+	for i, get := range getters {
+		service, err := get()
+		if err != nil {
+			return nil, err
+		}
+		services[i] = service
+	}
+	return services, nil
+}
+
+// Audit resolves the *Audit service.
+func (container *AppContainer) Audit() (service *Audit, err error) {
+	if container.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+	return container.get7()
+}
+
+// allAudit resolves every *Audit service.
+func (container *AppContainer) allAudit() ([]*Audit, error) {
+	getters := []func() (*Audit, error){
+		container.get7,
+	}
+	services := make([]*Audit, len(getters))
+	for i, get := range getters {
+		service, err := get()
+		if err != nil {
+			return nil, err
+		}
+		services[i] = service
+	}
+	return services, nil
+}
+
+// Remote resolves the *Remote service.
+func (container *AppContainer) Remote() (service *Remote, err error) {
+	if container.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+	return container.get8()
+}
+
+// allRemote resolves every *Remote service.
+func (container *AppContainer) allRemote() ([]*Remote, error) {
+	getters := []func() (*Remote, error){
+		container.get8,
+	}
+	services := make([]*Remote, len(getters))
+	for i, get := range getters {
+		service, err := get()
+		if err != nil {
+			return nil, err
+		}
+		services[i] = service
+	}
+	return services, nil
+}
+
+// get0 resolves the singleton *Config registered at example.go:17.
+func (container *AppContainer) get0() (service *Config, err error) {
+	owner := container.root
+	if owner.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+
+	owner.mutex0.Lock()
+	defer owner.mutex0.Unlock()
+	if !owner.created0.Load() {
+		if owner.service0, err = owner.create0(); err != nil {
+			return
+		}
+		owner.created0.Store(true)
+	}
+	return owner.service0, nil
+}
+
+// create0 creates an instance of *Config.
+func (container *AppContainer) create0() (instance *Config, err error) {
+	created := NewConfig()
+	if created != nil {
+		if err = container.track(created); err != nil {
+			return
+		}
+	}
+	return created, nil
+}
+
+// get1 resolves the singleton Repo registered at example.go:37.
+func (container *AppContainer) get1() (service Repo, err error) {
+	owner := container.root
+	if owner.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+
+	owner.mutex1.Lock()
+	defer owner.mutex1.Unlock()
+	if !owner.created1.Load() {
+		if owner.service1, err = owner.create1(); err != nil {
+			return
+		}
+		owner.created1.Store(true)
+	}
+	return owner.service1, nil
+}
+
+// create1 creates an instance of Repo.
+func (container *AppContainer) create1() (instance *SQLRepo, err error) {
+	a0, err := container.get0()
+	if err != nil {
+		return
+	}
+	created, err := NewSQLRepo(a0)
+	if err != nil {
+		return
+	}
+	if created != nil {
+		if err = container.track(created); err != nil {
+			return
+		}
+	}
+	return created, nil
+}
+
+// get2 resolves the singleton *SQLRepo registered at example.go:37.
+func (container *AppContainer) get2() (service *SQLRepo, err error) {
+	owner := container.root
+	if owner.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+
+	owner.mutex2.Lock()
+	defer owner.mutex2.Unlock()
+	if !owner.created2.Load() {
+		if owner.service2, err = owner.create2(); err != nil {
+			return
+		}
+		owner.created2.Store(true)
+	}
+	return owner.service2, nil
+}
+
+// create2 creates an instance of *SQLRepo.
+func (container *AppContainer) create2() (instance *SQLRepo, err error) {
+	a0, err := container.get0()
+	if err != nil {
+		return
+	}
+	created, err := NewSQLRepo(a0)
+	if err != nil {
+		return
+	}
+	if created != nil {
+		if err = container.track(created); err != nil {
+			return
+		}
+	}
+	return created, nil
+}
+
+// get3 resolves the singleton Repo registered at example.go:50.
+func (container *AppContainer) get3() (service Repo, err error) {
+	owner := container.root
+	if owner.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+
+	owner.mutex3.Lock()
+	defer owner.mutex3.Unlock()
+	if !owner.created3.Load() {
+		if owner.service3, err = owner.create3(); err != nil {
+			return
+		}
+		owner.created3.Store(true)
+	}
+	return owner.service3, nil
+}
+
+// create3 creates an instance of Repo.
+func (container *AppContainer) create3() (instance *MemoryRepo, err error) {
+	created := NewMemoryRepo()
+	if created != nil {
+		if err = container.track(created); err != nil {
+			return
+		}
+	}
+	return created, nil
+}
+
+// get4 resolves the transient io.Writer registered at example.go:55.
+func (container *AppContainer) get4() (service io.Writer, err error) {
+	return container.create4()
+}
+
+// create4 creates an instance of io.Writer.
+func (container *AppContainer) create4() (instance io.Writer, err error) {
+	created := NewLogWriter()
+	if created != nil {
+		if err = container.track(created); err != nil {
+			return
+		}
+	}
+	return created, nil
+}
+
+// get5 resolves the scoped *Handler registered at example.go:62.
+func (container *AppContainer) get5() (service *Handler, err error) {
+	if !container.IsScoped() {
+		err = di.ErrScopedServiceFromRoot
+		return
+	}
+	owner := container
+	if owner.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+
+	owner.mutex5.Lock()
+	defer owner.mutex5.Unlock()
+	if !owner.created5.Load() {
+		if owner.service5, err = owner.create5(); err != nil {
+			return
+		}
+		owner.created5.Store(true)
+	}
+	return owner.service5, nil
+}
+
+// create5 creates an instance of *Handler.
+func (container *AppContainer) create5() (instance *Handler, err error) {
+	a0, err := container.get1()
+	if err != nil {
+		return
+	}
+	created := &Handler{Repo: a0}
+	if err = container.track(created); err != nil {
+		return
+	}
+	return created, nil
+}
+
+// get6 resolves the scoped *Session registered at example.go:76.
+func (container *AppContainer) get6() (service *Session, err error) {
+	if !container.IsScoped() {
+		err = di.ErrScopedServiceFromRoot
+		return
+	}
+	owner := container
+	if owner.IsDisposed() {
+		err = di.ErrServiceContainerDisposed
+		return
+	}
+
+	owner.mutex6.Lock()
+	defer owner.mutex6.Unlock()
+	if !owner.created6.Load() {
+		if owner.service6, err = owner.create6(); err != nil {
+			return
+		}
+		owner.created6.Store(true)
+	}
+	return owner.service6, nil
+}
+
+// create6 creates an instance of *Session.
+func (container *AppContainer) create6() (instance *Session, err error) {
+	created := NewSession()
+	if created != nil {
+		if err = container.track(created); err != nil {
+			return
+		}
+	}
+	return created, nil
+}
+
+// get7 resolves the transient *Audit registered at example.go:83.
+func (container *AppContainer) get7() (service *Audit, err error) {
+	return container.create7()
+}
+
+// create7 creates an instance of *Audit.
+func (container *AppContainer) create7() (instance *Audit, err error) {
+	a0, err := container.allWriter()
+	if err != nil {
+		return
+	}
+	a1, err := container.allRepo()
+	if err != nil {
+		return
+	}
+	var a2 di.ServiceContainer = container
+	a3, err := container.get6()
+	if err != nil {
+		return
+	}
+	created := &Audit{Writers: a0, Repos: a1, Scope: a2, Session: a3}
+	if err = container.track(created); err != nil {
+		return
+	}
+	return created, nil
+}
+
+// get8 resolves the transient *Remote registered at example.go:95.
+func (container *AppContainer) get8() (service *Remote, err error) {
+	return container.create8()
+}
+
+// create8 creates an instance of *Remote.
+func (container *AppContainer) create8() (instance *Remote, err error) {
+	created, err := NewRemote()
+	if err != nil {
+		return
+	}
+	if created != nil {
+		if err = container.track(created); err != nil {
+			return
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,100 @@
+//go:generate go run github.com/go-mike/di/cmd/di-gen -container AppContainer
+
+package example
+
+import (
+	"errors"
+	"io"
+
+	"github.com/go-mike/di"
+)
+
+type Config struct {
+	DSN string
+}
+
+//di:singleton
+func NewConfig() *Config {
+	return &Config{DSN: "memory"}
+}
+
+type Repo interface {
+	Find(id int) string
+}
+
+type SQLRepo struct {
+	config *Config
+}
+
+func (repo *SQLRepo) Find(id int) string {
+	return repo.config.DSN
+}
+
+// NewSQLRepo is registered twice, as the primary repository and as itself.
+//
+//di:singleton as=Repo name=primary order=1
+//di:singleton
+func NewSQLRepo(config *Config) (*SQLRepo, error) {
+	return &SQLRepo{config: config}, nil
+}
+
+type MemoryRepo struct{}
+
+func (*MemoryRepo) Find(id int) string {
+	return "cache"
+}
+
+// NewMemoryRepo is resolved before the primary repository in slices.
+//
+//di:singleton as=Repo order=-1
+func NewMemoryRepo() *MemoryRepo {
+	return &MemoryRepo{}
+}
+
+//di:transient tags=io,logging key=log
+func NewLogWriter() io.Writer {
+	return io.Discard
+}
+
+// Handler is created for each scope.
+//
+//di:scoped
+type Handler struct {
+	Repo Repo
+}
+
+// Session is disposed with its scope.
+type Session struct {
+	Disposed bool
+}
+
+func (session *Session) Dispose() {
+	session.Disposed = true
+}
+
+//di:scoped
+func NewSession() *Session {
+	return &Session{}
+}
+
+// Audit is created each time, from the scope requiring it.
+//
+//di:transient
+type Audit struct {
+	Writers []io.Writer
+	Repos   []Repo
+	Scope   di.ServiceContainer
+	Session *Session
+}
+
+var ErrUnavailable = errors.New("unavailable")
+
+type Remote struct{}
+
+//di:transient
+func NewRemote() (*Remote, error) {
+	return nil, ErrUnavailable
+}
+
+// Unannotated declarations are not registered.
+type Unregistered struct{}
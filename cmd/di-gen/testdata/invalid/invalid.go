@@ -0,0 +1,29 @@
+package invalid
+
+type Service struct{}
+
+//di:singleton as=Missing
+func NewService() *Service {
+	return &Service{}
+}
+
+//di:scoped
+func (*Service) Method() *Service {
+	return nil
+}
+
+//di:transient
+func NewNothing() {}
+
+//di:forever
+type Value struct{}
+
+//di:singleton
+type Alias = Service
+
+type Target interface {
+	Target()
+}
+
+//di:transient as=Target
+type Other struct{}
@@ -0,0 +1,8 @@
+package perresolution
+
+type Work struct{}
+
+//di:perresolution
+func NewWork() *Work {
+	return &Work{}
+}
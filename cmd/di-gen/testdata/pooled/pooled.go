@@ -0,0 +1,8 @@
+package pooled
+
+import "bytes"
+
+//di:pooled
+func NewBuffer() *bytes.Buffer {
+	return &bytes.Buffer{}
+}
@@ -0,0 +1,20 @@
+// Command di-vet reports misuses of the di package which are visible
+// statically. See package dicheck for the reported misuses.
+//
+// Run it directly on packages, or as a vet tool:
+//
+//	go install github.com/go-mike/di/cmd/di-vet
+//	go vet -vettool=$(which di-vet) ./...
+//
+// The -unregistered=false flag disables the report of GetService calls on
+// service types never registered in the same package.
+package main
+
+import (
+	"github.com/go-mike/di/dicheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(dicheck.Analyzer)
+}
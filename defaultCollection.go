@@ -1,7 +1,9 @@
 package di
 
 import (
+	"errors"
 	"reflect"
+	"time"
 
 	"golang.org/x/exp/slices"
 )
@@ -9,6 +11,9 @@ import (
 // ServiceCollection is a collection of services, describing a dependency graph of services.
 type defaultCollection struct {
 	descriptors []ServiceDescriptor
+	hooks       []ContainerHooks
+	modules     moduleInstaller
+	validators  []func(provider ServiceProvider) error
 }
 
 // NewServiceCollection creates a new ServiceCollection.
@@ -88,14 +93,128 @@ func (services *defaultCollection) TryAddRange(descriptors ...ServiceDescriptor)
 	return services
 }
 
+func (services *defaultCollection) TryAddEnumerable(descriptor ServiceDescriptor) ServiceCollection {
+	serviceType := descriptor.ServiceType()
+	implementation := implementationOf(descriptor.Factory())
+	exists := anySlice(services.descriptors, func(existing ServiceDescriptor) bool {
+		return existing.ServiceType() == serviceType &&
+			implementationOf(existing.Factory()) == implementation
+	})
+	if exists {
+		return services
+	}
+	return services.Add(descriptor)
+}
+
+func (services *defaultCollection) TryAddEnumerableRange(descriptors ...ServiceDescriptor) ServiceCollection {
+	for _, descriptor := range descriptors {
+		services.TryAddEnumerable(descriptor)
+	}
+	return services
+}
+
+func (services *defaultCollection) Replace(descriptor ServiceDescriptor) ServiceCollection {
+	serviceType := descriptor.ServiceType()
+	index := slices.IndexFunc(services.descriptors, func(existing ServiceDescriptor) bool {
+		return existing.ServiceType() == serviceType
+	})
+	if index < 0 {
+		return services.Add(descriptor)
+	}
+	services.descriptors[index] = descriptor
+	return services
+}
+
+func (services *defaultCollection) Remove(descriptor ServiceDescriptor) ServiceCollection {
+	services.descriptors = filterSlice(services.descriptors, func(existing ServiceDescriptor) bool {
+		return existing != descriptor
+	})
+	return services
+}
+
+func (services *defaultCollection) RemoveAll(serviceType reflect.Type) ServiceCollection {
+	services.descriptors = filterSlice(services.descriptors, func(existing ServiceDescriptor) bool {
+		return existing.ServiceType() != serviceType
+	})
+	return services
+}
+
+func (services *defaultCollection) AddModule(module Module) ServiceCollection {
+	services.modules.add(services, module)
+	return services
+}
+
+func (services *defaultCollection) AddValidator(validator func(provider ServiceProvider) error) ServiceCollection {
+	services.validators = append(services.validators, validator)
+	return services
+}
+
+func (services *defaultCollection) AddHooks(hooks ContainerHooks) ServiceCollection {
+	services.hooks = append(services.hooks, hooks)
+	return services
+}
+
 func (services *defaultCollection) Build() (ServiceContainer, error) {
-	describer, err := newDefaultDescriber(services.descriptors)
+	return services.BuildWithOptions(BuildOptions{})
+}
+
+func (services *defaultCollection) BuildWithOptions(options BuildOptions) (ServiceContainer, error) {
+	hooks := combineHooks(append(cloneSlice(services.hooks), options.Hooks...))
+
+	var start time.Time
+	if hooks.OnBuild != nil {
+		start = time.Now()
+	}
+
+	container, err := services.build(hooks, options)
+
+	if hooks.OnBuild != nil {
+		hooks.OnBuild(BuildEvent{
+			Descriptors: len(services.descriptors),
+			Duration:    time.Since(start),
+			Err:         err,
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return container, nil
+}
+
+func (services *defaultCollection) build(hooks ContainerHooks, options BuildOptions) (*defaultContainer, error) {
+	if err := services.modules.validate(); err != nil {
+		return nil, err
+	}
+
+	describer, err := newDefaultDescriberWith(services.descriptors, options.validationRules())
 	if err != nil {
 		return nil, err
 	}
-	return newDefaultContainer(
-		describer,
-		slices.Clone(services.descriptors),
-		nil,
-	)
+
+	container, err := newDefaultContainer(describer, hooks, nil)
+	if err != nil {
+		return nil, err
+	}
+	container.rejectNil = options.NilInstances == NilInstancesRejected
+
+	validators := services.validators
+	if options.WarmUp != nil {
+		warmUp := *options.WarmUp
+		validators = append(cloneSlice(validators), func(provider ServiceProvider) error {
+			return warmUpSingletons(provider, services.descriptors, warmUp)
+		})
+	}
+
+	errs := filterSlice(
+		mapSlice(validators, func(validator func(ServiceProvider) error) error {
+			return validator(container)
+		}),
+		func(err error) bool { return err != nil })
+	if len(errs) > 0 {
+		container.Dispose()
+		return nil, errors.Join(errs...)
+	}
+
+	return container, nil
 }
@@ -245,3 +245,150 @@ func TestServiceCollection_Build(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, scope)
 }
+
+func TestServiceCollection_AddValidator(t *testing.T) {
+	var disposed []string
+	services := NewServiceCollection().Add(
+		NewSingletonFactory[*testDisposeRecorder](func(ServiceProvider) (any, error) {
+			return &testDisposeRecorder{name: "recorder", disposed: &disposed}, nil
+		}))
+	services.AddValidator(func(provider ServiceProvider) error {
+		_, err := GetService[*testDisposeRecorder](provider)
+		return err
+	})
+	services.AddValidator(func(provider ServiceProvider) error {
+		return errTestFactory
+	})
+
+	container, err := services.Build()
+	assert.Nil(t, container)
+	assert.ErrorIs(t, err, errTestFactory)
+	assert.Equal(t, []string{"recorder"}, disposed)
+}
+
+func TestServiceCollection_Replace(t *testing.T) {
+	services := NewServiceCollection()
+	descriptor1, _ := NewInstance(&testStructWithFields{Field1: 1})
+	descriptor2, _ := NewInstance(&testDummyDisposable{})
+	descriptor3, _ := NewInstance(&testStructWithFields{Field1: 3})
+	services.AddRange(descriptor1, descriptor2, descriptor3)
+
+	// The first descriptor of the type is replaced at its position
+	replacement, _ := NewInstance(&testStructWithFields{Field1: 4})
+	newServices := services.Replace(replacement)
+	assert.Same(t, services, newServices)
+	assert.Equal(t, []ServiceDescriptor{replacement, descriptor2, descriptor3}, services.ListDescriptors())
+
+	// A descriptor without a type registered is added
+	added, _ := NewInstance(&testDummyNonDisposable{})
+	services.Replace(added)
+	assert.Equal(t, []ServiceDescriptor{replacement, descriptor2, descriptor3, added}, services.ListDescriptors())
+}
+
+func TestServiceCollection_Remove(t *testing.T) {
+	services := NewServiceCollection()
+	descriptor1, _ := NewInstance(&testStructWithFields{Field1: 1})
+	descriptor2, _ := NewInstance(&testDummyDisposable{})
+	descriptor3, _ := NewInstance(&testStructWithFields{Field1: 3})
+	services.AddRange(descriptor1, descriptor2, descriptor3)
+
+	newServices := services.Remove(descriptor1)
+	assert.Same(t, services, newServices)
+	assert.Equal(t, []ServiceDescriptor{descriptor2, descriptor3}, services.ListDescriptors())
+
+	// Removing a descriptor not in the collection does nothing
+	services.Remove(descriptor1)
+	assert.Equal(t, []ServiceDescriptor{descriptor2, descriptor3}, services.ListDescriptors())
+}
+
+func TestServiceCollection_RemoveAll(t *testing.T) {
+	services := NewServiceCollection()
+	descriptor1, _ := NewInstance(&testStructWithFields{Field1: 1})
+	descriptor2, _ := NewInstance(&testDummyDisposable{})
+	descriptor3, _ := NewInstance(&testStructWithFields{Field1: 3})
+	descriptor4, _ := NewInstance(&testDummyNonDisposable{})
+	services.AddRange(descriptor1, descriptor2, descriptor3, descriptor4)
+
+	newServices := services.RemoveAll(typeOfTestStructWithFieldsPtr)
+	assert.Same(t, services, newServices)
+	assert.Equal(t, []ServiceDescriptor{descriptor2, descriptor4}, services.ListDescriptors())
+}
+
+func TestReplaceOf(t *testing.T) {
+	services := NewServiceCollection()
+	descriptor1, _ := NewInstance(&testStructWithFields{Field1: 1})
+	descriptor2, _ := NewInstance(&testDummyDisposable{})
+	services.AddRange(descriptor1, descriptor2)
+
+	fake := &testStructWithFields{Field1: 2}
+	ReplaceOf[*testStructWithFields](services, Scoped, NewFactory(func(ServiceProvider) (any, error) {
+		return fake, nil
+	}))
+
+	descriptors := services.ListDescriptors()
+	assert.Len(t, descriptors, 2)
+	assert.Equal(t, typeOfTestStructWithFieldsPtr, descriptors[0].ServiceType())
+	assert.Equal(t, Scoped, descriptors[0].Lifetime())
+	assert.Same(t, descriptor2, descriptors[1])
+}
+
+func TestRemoveAllOf(t *testing.T) {
+	services := NewServiceCollection()
+	descriptor1, _ := NewInstance(&testStructWithFields{Field1: 1})
+	descriptor2, _ := NewInstance(&testDummyDisposable{})
+	services.AddRange(descriptor1, descriptor2)
+
+	RemoveAllOf[*testStructWithFields](services)
+	assert.Equal(t, []ServiceDescriptor{descriptor2}, services.ListDescriptors())
+}
+
+func TestServiceCollection_TryAddEnumerable(t *testing.T) {
+	newHandler := func(ServiceProvider) (any, error) { return &testStructWithFields{}, nil }
+	otherHandler := func(ServiceProvider) (any, error) { return &testStructWithFields{}, nil }
+
+	services := NewServiceCollection()
+	descriptor1 := NewTransientFactory[*testStructWithFields](newHandler)
+	newServices := services.TryAddEnumerable(descriptor1)
+	assert.Same(t, services, newServices)
+
+	// Same service type and function: skipped, even with another lifetime
+	services.TryAddEnumerable(NewSingletonFactory[*testStructWithFields](newHandler))
+	// Another function for the same service type: added
+	descriptor2 := NewTransientFactory[*testStructWithFields](otherHandler)
+	services.TryAddEnumerable(descriptor2)
+	// Same function for another service type: added
+	descriptor3 := NewTransientFactory[*testDummyDisposable](newHandler)
+	services.TryAddEnumerable(descriptor3)
+
+	assert.Equal(t, []ServiceDescriptor{descriptor1, descriptor2, descriptor3}, services.ListDescriptors())
+}
+
+func TestServiceCollection_TryAddEnumerableStructsAndInstances(t *testing.T) {
+	services := NewServiceCollection()
+	struct1, _ := NewSingletonStruct[testServiceInterface, testServiceStruct]()
+	struct2, _ := NewScopedStruct[testServiceInterface, testServiceStruct]()
+	instance := &testStructWithFields{Field1: 1}
+	instance1, _ := NewInstance(instance)
+	instance2, _ := NewInstance(instance)
+	instance3, _ := NewInstance(&testStructWithFields{Field1: 1})
+
+	services.TryAddEnumerableRange(struct1, struct2, instance1, instance2, instance3)
+
+	assert.Equal(t, []ServiceDescriptor{struct1, instance1, instance3}, services.ListDescriptors())
+}
+
+func TestServiceCollection_TryAddEnumerableDisplayNames(t *testing.T) {
+	newFactory := func(name string) ServiceFactory {
+		return NewFactoryWith(name, nil, func(ServiceProvider) (any, error) {
+			return &testStructWithFields{}, nil
+		})
+	}
+	services := NewServiceCollection()
+	descriptor1 := NewSingletonServiceFactory[*testStructWithFields](newFactory("first"))
+	descriptor2 := NewSingletonServiceFactory[*testStructWithFields](newFactory("first"))
+	descriptor3 := NewSingletonServiceFactory[*testStructWithFields](newFactory("second"))
+
+	services.TryAddEnumerableRange(descriptor1, descriptor2, descriptor3)
+
+	assert.Equal(t, []ServiceDescriptor{descriptor1, descriptor3}, services.ListDescriptors())
+}
@@ -2,52 +2,151 @@ package di
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
 	ErrServiceContainerDisposed = errors.New("service container has been disposed")
-	ErrMissingServiceDescriber    = errors.New("missing service describer")
+	ErrMissingServiceDescriber  = errors.New("missing service describer")
+	ErrScopedServiceFromRoot    = errors.New("scoped service requested from root container")
+	ErrCircularDependency       = errors.New("circular dependency")
+	ErrUnknownLifetime          = errors.New("unknown lifetime")
 )
 
+// lastScopeID is the last identifier given to a container or scope.
+var lastScopeID uint64
+
 type defaultContainer struct {
-	mutex     sync.Mutex
-	describer ServiceDescriber
-	parent    ServiceProvider
-	data      []*descriptorData
+	mutex       sync.Mutex
+	id          uint64
+	describer   ServiceDescriber
+	hooks       ContainerHooks
+	root        *defaultContainer
+	parent      *defaultContainer
+	data        map[ServiceDescriptor]*descriptorData
+	disposables []descriptorInstance
+	disposed    bool
+	// rejectNil fails the resolution of nil instances
+	rejectNil bool
+	// supplied holds the instances supplied to the scope by scope-supplied descriptor
+	supplied map[ServiceDescriptor]any
+	// pools holds the pools of pooled descriptors, in the root container
+	pools map[ServiceDescriptor]*servicePool
+	// managers holds the managers of custom lifetimes, in the root container
+	managers map[Lifetime]LifetimeManager
 }
 
+// descriptorData holds the cached instance of a singleton or scoped descriptor.
 type descriptorData struct {
+	mutex      sync.Mutex
+	descriptor ServiceDescriptor
+	instance   ServiceInstance
+	created    atomic.Bool
+}
+
+// descriptorInstance is a service instance to be disposed with its container.
+type descriptorInstance struct {
 	descriptor ServiceDescriptor
-	instances  []ServiceInstance
+	disposable Disposable
+}
+
+// stopper is implemented by disposable instances running concurrently, which
+// are stopped before any instance of their container is disposed.
+type stopper interface {
+	stopBeforeDispose()
 }
 
 var _ ServiceContainer = (*defaultContainer)(nil)
+var _ singletonWarmer = (*defaultContainer)(nil)
 var _ ServiceProvider = (*defaultContainer)(nil)
+var _ taggedServiceResolver = (*defaultContainer)(nil)
+var _ poolStatsProvider = (*defaultContainer)(nil)
 
 // Provider implements ServiceContainer
 func (scope *defaultContainer) Provider() ServiceProvider {
 	return scope
 }
 
-// Dispose implements ServiceContainer
+// CreateScope implements ServiceContainer
+func (scope *defaultContainer) CreateScope() (ServiceContainer, error) {
+	return scope.CreateScopeWith()
+}
+
+// CreateScopeWith implements ServiceContainer.
+// The child scope also inherits the values supplied to this scope.
+func (scope *defaultContainer) CreateScopeWith(values ...ScopeValue) (ServiceContainer, error) {
+	if scope.IsDisposed() {
+		return nil, ErrServiceContainerDisposed
+	}
+	supplied, err := scopeValues(scope.describer, scope.supplied, values)
+	if err != nil {
+		return nil, err
+	}
+	child, err := newDefaultContainer(scope.describer, scope.hooks, scope)
+	if err != nil {
+		return nil, err
+	}
+	child.supply(supplied)
+	return child, nil
+}
+
+// supply caches the supplied instances as created. They are owned by the
+// code supplying them, so they are not disposed with the scope.
+func (scope *defaultContainer) supply(supplied map[ServiceDescriptor]any) {
+	scope.supplied = supplied
+	for descriptor, instance := range supplied {
+		data := &descriptorData{descriptor: descriptor, instance: ServiceInstance{Instance: instance}}
+		data.created.Store(true)
+		scope.data[descriptor] = data
+	}
+}
+
+// Dispose implements ServiceContainer.
+// Instances running concurrently, such as background workers, are stopped
+// first, so that they never use an instance already disposed. Instances are
+// then disposed in reverse creation order, and a panicking instance does not
+// prevent the remaining ones from being disposed.
 func (scope *defaultContainer) Dispose() {
 	scope.mutex.Lock()
-	defer scope.mutex.Unlock()
-	if !scope.IsDisposed() {
-		for _, data := range scope.data {
-			for _, instance := range data.instances {
-				instance.Disposable.Dispose()
-			}
+	if scope.disposed {
+		scope.mutex.Unlock()
+		return
+	}
+	scope.disposed = true
+	disposables := scope.disposables
+	scope.disposables = nil
+	scope.data = nil
+	scope.mutex.Unlock()
+
+	for i := len(disposables) - 1; i >= 0; i-- {
+		if stoppable, ok := disposables[i].disposable.(stopper); ok {
+			scope.guardDispose(disposables[i].descriptor, stoppable.stopBeforeDispose)
 		}
-		scope.data = nil
+	}
+	if scope.parent == nil {
+		// Idle pooled instances and the instances of custom lifetimes are
+		// disposed before the root's instances they depend on
+		scope.closePools()
+		scope.disposeManagers()
+	}
+	for i := len(disposables) - 1; i >= 0; i-- {
+		scope.disposeInstance(disposables[i])
+	}
+
+	if hook := scope.hooks.OnScopeDisposed; hook != nil {
+		hook(scope.scopeEvent())
 	}
 }
 
 // IsDisposed implements ServiceContainer
 func (scope *defaultContainer) IsDisposed() bool {
-	return scope.data == nil
+	scope.mutex.Lock()
+	defer scope.mutex.Unlock()
+	return scope.disposed
 }
 
 // IsScoped implements ServiceContainer
@@ -61,16 +160,20 @@ func (scope *defaultContainer) GetService(serviceType reflect.Type) (any, error)
 		return nil, ErrServiceContainerDisposed
 	}
 
-	scope.mutex.Lock()
-	defer scope.mutex.Unlock()
-
-	data := scope.findDescriptorData(serviceType)
-
-	if data == nil {
+	instance, err := scope.resolve(newResolution(scope), serviceType)
 
+	if err != nil {
+		if hook := scope.hooks.OnResolveFailed; hook != nil {
+			hook(ResolveFailedEvent{
+				ServiceType: serviceType,
+				ScopeID:     scope.id,
+				Err:         err,
+			})
+		}
+		return nil, err
 	}
 
-	return nil, nil
+	return instance, nil
 }
 
 // GetServiceInfo implements ServiceProvider
@@ -85,36 +188,689 @@ func (scope *defaultContainer) GetServiceInfo(serviceType reflect.Type) ServiceI
 		return newNotFoundServiceInfo(serviceType)
 	}
 
+	owner := scope
+	if descriptor.Lifetime() == Singleton {
+		owner = scope.root
+	}
+
+	return newServiceInfo(serviceType, owner.isInstantiated(descriptor), descriptor.Lifetime())
+}
+
+func (scope *defaultContainer) isInstantiated(descriptor ServiceDescriptor) bool {
+	scope.mutex.Lock()
+	defer scope.mutex.Unlock()
+
+	data, ok := scope.data[descriptor]
+	return ok && data.created.Load()
+}
+
+// resolve resolves a service type, or every descriptor of the element type
+// when the service type is a slice.
+func (scope *defaultContainer) resolve(res *resolution, serviceType reflect.Type) (any, error) {
+	if serviceType == nil {
+		return nil, newServiceResolutionError(res.chain, serviceType, ErrServiceNotFound)
+	}
+
+	if ownedServiceType, isOwned := unwrapOwned(serviceType); isOwned {
+		return scope.resolveOwned(res, serviceType, ownedServiceType)
+	}
+
+	if serviceType.Kind() == reflect.Slice {
+		elemType, _ := unwrapMeta(serviceType.Elem())
+		return scope.resolveAll(res, serviceType, scope.describer.GetServiceDescriptors(elemType))
+	}
+
+	metaServiceType, isMeta := unwrapMeta(serviceType)
+	descriptor := scope.describer.GetServiceDescriptor(metaServiceType)
+	if descriptor == nil && serviceType == typeOfServiceContainer {
+		return scope, nil
+	}
+	if descriptor == nil {
+		return nil, newServiceResolutionError(res.chain, serviceType, ErrServiceNotFound)
+	}
+
+	instance, err := scope.resolveDescriptor(res, descriptor)
+	if err != nil || !isMeta {
+		return instance, err
+	}
+	return newMeta(serviceType, instance, descriptor), nil
+}
+
+// resolveOwned resolves a service in a new child scope, disposed by the
+// Owned value wrapping the service.
+func (scope *defaultContainer) resolveOwned(
+	res *resolution,
+	ownedType reflect.Type,
+	serviceType reflect.Type,
+) (any, error) {
+	child, err := newDefaultContainer(scope.describer, scope.hooks, scope)
+	if err != nil {
+		return nil, err
+	}
+	child.supply(scope.supplied)
+
+	owned := newResolution(child)
+	owned.chain = res.chain
+	instance, err := child.resolve(owned, serviceType)
+	if err != nil {
+		child.Dispose()
+		return nil, err
+	}
+	return newOwned(ownedType, instance, child), nil
+}
+
+// resolveAll resolves the given descriptors into a slice, wrapping each
+// instance with its metadata for slices of Meta.
+func (scope *defaultContainer) resolveAll(
+	res *resolution,
+	sliceType reflect.Type,
+	descriptors []ServiceDescriptor,
+) (any, error) {
+	elemType := sliceType.Elem()
+	_, isMeta := unwrapMeta(elemType)
+	result := reflect.MakeSlice(sliceType, 0, len(descriptors))
+
+	for _, descriptor := range descriptors {
+		instance, err := scope.resolveDescriptor(res, descriptor)
+		if err != nil {
+			return nil, err
+		}
+		if isMeta {
+			instance = newMeta(elemType, instance, descriptor)
+		}
+		if instance == nil {
+			result = reflect.Append(result, reflect.Zero(elemType))
+		} else {
+			result = reflect.Append(result, reflect.ValueOf(instance))
+		}
+	}
+
+	return result.Interface(), nil
+}
+
+// getServicesWithTag implements taggedServiceResolver
+func (scope *defaultContainer) getServicesWithTag(serviceType reflect.Type, tag string) (any, error) {
+	if scope.IsDisposed() {
+		return nil, ErrServiceContainerDisposed
+	}
+	return scope.resolveWithTag(newResolution(scope), serviceType, tag)
+}
+
+func (scope *defaultContainer) resolveWithTag(res *resolution, serviceType reflect.Type, tag string) (any, error) {
+	descriptors := filterSlice(scope.describer.GetServiceDescriptors(serviceType), HasTag(tag))
+	return scope.resolveAll(res, reflect.SliceOf(serviceType), descriptors)
+}
+
+// resolveDescriptor resolves a descriptor from the container owning its lifetime.
+func (scope *defaultContainer) resolveDescriptor(res *resolution, descriptor ServiceDescriptor) (any, error) {
+	if findSlice(res.chain, func(d ServiceDescriptor) bool { return d == descriptor }) != nil {
+		return nil, newServiceResolutionError(res.chain, descriptor.ServiceType(), ErrCircularDependency)
+	}
+
+	switch descriptor.Lifetime() {
+	case Singleton:
+		return scope.root.getOrCreate(res, descriptor)
+	case Scoped:
+		if !scope.IsScoped() {
+			return nil, newServiceResolutionError(res.chain, descriptor.ServiceType(), ErrScopedServiceFromRoot)
+		}
+		return scope.getOrCreate(res, descriptor)
+	case Transient:
+		instance, err := scope.instantiate(res, descriptor)
+		return instance.Instance, err
+	case Pooled:
+		return scope.rent(res, descriptor)
+	case PerResolution:
+		return scope.resolveInGraph(res, descriptor)
+	default:
+		return scope.resolveManaged(res, descriptor)
+	}
+}
+
+// warmUp instantiates the selected singletons level by level, skipping the
+// ones requiring a singleton that failed. Within a level, up to parallelism
+// singletons are instantiated concurrently.
+func (scope *defaultContainer) warmUp(
+	descriptors []ServiceDescriptor,
+	selected []ServiceDescriptor,
+	parallelism int,
+) error {
+	levels, err := warmUpLevels(descriptors, selected)
+	if err != nil {
+		return err
+	}
+	if parallelism < 1 || hasRequirementCycle(descriptors, levels) {
+		parallelism = 1
+	}
+
+	var errs []error
+	failed := map[reflect.Type]bool{}
+	for _, level := range levels {
+		results := make([]error, len(level))
+		semaphore := make(chan struct{}, parallelism)
+		var wg sync.WaitGroup
+
+		for i, node := range level {
+			if requiresFailed(node, failed) {
+				results[i] = errWarmUpSkipped
+				continue
+			}
+			wg.Add(1)
+			semaphore <- struct{}{}
+			go func(i int, descriptor ServiceDescriptor) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+				_, results[i] = scope.root.resolveDescriptor(newResolution(scope.root), descriptor)
+			}(i, node.descriptor)
+		}
+		wg.Wait()
+
+		for i, err := range results {
+			if err == nil {
+				continue
+			}
+			failed[level[i].descriptor.ServiceType()] = true
+			if err != errWarmUpSkipped {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// errWarmUpSkipped marks the singletons not instantiated by warmUp.
+var errWarmUpSkipped = errors.New("skipped")
+
+func requiresFailed(node warmUpNode, failed map[reflect.Type]bool) bool {
+	for serviceType := range failed {
+		if node.requires[serviceType] {
+			return true
+		}
+	}
+	return false
+}
+
+// getOrCreate returns the cached instance of a descriptor, creating it once.
+func (scope *defaultContainer) getOrCreate(res *resolution, descriptor ServiceDescriptor) (any, error) {
+	data, err := scope.getDescriptorData(descriptor)
+	if err != nil {
+		return nil, newServiceResolutionError(res.chain, descriptor.ServiceType(), err)
+	}
+
+	data.mutex.Lock()
+	defer data.mutex.Unlock()
+
+	if data.created.Load() {
+		scope.onResolved(descriptor, true)
+		return data.instance.Instance, nil
+	}
+
+	instance, err := scope.instantiate(res, descriptor)
+	if err != nil {
+		return nil, err
+	}
+
+	data.instance = instance
+	data.created.Store(true)
+	return instance.Instance, nil
+}
+
+func (scope *defaultContainer) getDescriptorData(descriptor ServiceDescriptor) (*descriptorData, error) {
+	scope.mutex.Lock()
+	defer scope.mutex.Unlock()
+
+	if scope.disposed {
+		return nil, ErrServiceContainerDisposed
+	}
+
+	data, ok := scope.data[descriptor]
+	if !ok {
+		data = &descriptorData{descriptor: descriptor}
+		scope.data[descriptor] = data
+	}
+	return data, nil
+}
+
+// instantiate invokes the descriptor's factory and tracks the new instance
+// for disposal.
+func (scope *defaultContainer) instantiate(res *resolution, descriptor ServiceDescriptor) (ServiceInstance, error) {
+	instance, err := scope.create(res, descriptor)
+	if err != nil {
+		return ServiceInstance{}, err
+	}
+
+	if err := scope.track(descriptor, instance.Disposable); err != nil {
+		return ServiceInstance{}, newServiceResolutionError(res.chain, descriptor.ServiceType(), err)
+	}
+
+	scope.onResolved(descriptor, false)
+	return instance, nil
+}
+
+// create invokes the descriptor's factory.
+func (scope *defaultContainer) create(res *resolution, descriptor ServiceDescriptor) (ServiceInstance, error) {
+	child := &resolution{
+		scope: scope,
+		chain: append(cloneSlice(res.chain), descriptor),
+		graph: res.graph,
+	}
+
+	hook := scope.hooks.OnInstantiated
+	var start time.Time
+	if hook != nil {
+		start = time.Now()
+	}
+
+	instance, err := descriptor.Factory().Factory()(child)
+
+	if hook != nil {
+		hook(InstantiatedEvent{
+			Descriptor: descriptor,
+			ScopeID:    scope.id,
+			Duration:   time.Since(start),
+			Err:        err,
+		})
+	}
+
+	if err != nil {
+		return ServiceInstance{}, wrapServiceResolutionError(res.chain, descriptor.ServiceType(), err)
+	}
+	if scope.rejectNil && isNil(instance.Instance) {
+		return ServiceInstance{}, newServiceResolutionError(res.chain, descriptor.ServiceType(), ErrNilServiceInstance)
+	}
+	return instance, nil
+}
+
+// resolveManaged resolves a descriptor of a custom lifetime from its manager.
+func (scope *defaultContainer) resolveManaged(res *resolution, descriptor ServiceDescriptor) (any, error) {
+	manager, err := scope.root.lifetimeManager(descriptor.Lifetime())
+	if err != nil {
+		return nil, newServiceResolutionError(res.chain, descriptor.ServiceType(), err)
+	}
+
+	created := false
+	instance, err := manager.GetOrCreate(
+		LifetimeRequest{Descriptor: descriptor, Scope: scope, Provider: res},
+		func() (ServiceInstance, error) {
+			created = true
+			return scope.createInScope(res, descriptor, scope.supplied)
+		})
+	if err != nil {
+		return nil, wrapServiceResolutionError(res.chain, descriptor.ServiceType(), err)
+	}
+
+	scope.onResolved(descriptor, !created)
+	return instance, nil
+}
+
+// createInScope creates an instance outliving the scope requesting it, such
+// as a pooled instance or an instance of a custom lifetime, in a new scope of
+// the root container supplied with the given values. Its dependencies live
+// as long as the instance, as disposing the instance disposes the new scope.
+func (scope *defaultContainer) createInScope(
+	res *resolution,
+	descriptor ServiceDescriptor,
+	supplied map[ServiceDescriptor]any,
+) (ServiceInstance, error) {
+	owner, err := newDefaultContainer(scope.describer, scope.hooks, scope.root)
+	if err != nil {
+		return ServiceInstance{}, err
+	}
+	owner.supply(supplied)
+
+	managed := newResolution(owner)
+	managed.chain = res.chain
+	instance, err := owner.create(managed, descriptor)
+	if err == nil {
+		err = owner.track(descriptor, instance.Disposable)
+	}
+	if err != nil {
+		owner.Dispose()
+		return ServiceInstance{}, err
+	}
+	return ServiceInstance{Instance: instance.Instance, Disposable: owner}, nil
+}
+
+// lifetimeManager returns the manager of a custom lifetime, from the root
+// container.
+func (scope *defaultContainer) lifetimeManager(lifetime Lifetime) (LifetimeManager, error) {
+	custom, ok := lookupLifetime(lifetime)
+	if !ok {
+		return nil, ErrUnknownLifetime
+	}
+
 	scope.mutex.Lock()
 	defer scope.mutex.Unlock()
+	if scope.disposed {
+		return nil, ErrServiceContainerDisposed
+	}
+	manager, ok := scope.managers[lifetime]
+	if !ok {
+		manager = custom.newManager()
+		if scope.managers == nil {
+			scope.managers = map[Lifetime]LifetimeManager{}
+		}
+		scope.managers[lifetime] = manager
+	}
+	return manager, nil
+}
+
+// disposeManagers disposes the managers of custom lifetimes of the root
+// container.
+func (scope *defaultContainer) disposeManagers() {
+	scope.mutex.Lock()
+	managers := scope.managers
+	scope.managers = nil
+	scope.mutex.Unlock()
+
+	for _, manager := range managers {
+		manager.Dispose()
+	}
+}
+
+// resolveInGraph resolves a per-resolution descriptor, instantiated once
+// for the top-level request being resolved. Instances are not shared across
+// containers, so that singletons never capture an instance disposed with a
+// scope.
+func (scope *defaultContainer) resolveInGraph(res *resolution, descriptor ServiceDescriptor) (any, error) {
+	key := graphKey{descriptor: descriptor, scope: scope}
+	if instance, ok := res.graph.get(key); ok {
+		scope.onResolved(descriptor, true)
+		return instance, nil
+	}
+
+	instance, err := scope.instantiate(res, descriptor)
+	if err != nil {
+		return nil, err
+	}
+	res.graph.set(key, instance.Instance)
+	return instance.Instance, nil
+}
+
+// rent takes an instance of a pooled descriptor from its pool, resetting it,
+// or else creates one in its own scope, disposed when the pool discards the
+// instance. The instance returns to the pool when the renting scope is
+// disposed.
+func (scope *defaultContainer) rent(res *resolution, descriptor ServiceDescriptor) (any, error) {
+	pool := scope.root.pool(descriptor)
+	instance, reused := pool.take()
+	if reused {
+		if resettable, ok := instance.Instance.(Resettable); ok {
+			resettable.Reset()
+		}
+	} else {
+		created, err := scope.createInScope(res, descriptor, nil)
+		if err != nil {
+			return nil, err
+		}
+		instance = created
+		pool.created()
+	}
+
+	if err := scope.track(descriptor, &pooledInstance{pool: pool, instance: instance}); err != nil {
+		return nil, newServiceResolutionError(res.chain, descriptor.ServiceType(), err)
+	}
 
-	data := scope.findDescriptorData(serviceType)
-	isInstantiated := data != nil
+	scope.onResolved(descriptor, reused)
+	return instance.Instance, nil
+}
 
-	return newServiceInfo(serviceType, isInstantiated, descriptor.Lifetime())
+// pool returns the pool of a pooled descriptor, from the root container.
+func (scope *defaultContainer) pool(descriptor ServiceDescriptor) *servicePool {
+	scope.mutex.Lock()
+	defer scope.mutex.Unlock()
+	pool, ok := scope.pools[descriptor]
+	if !ok {
+		pool = newServicePool(descriptor)
+		if scope.disposed {
+			pool.close()
+		}
+		if scope.pools == nil {
+			scope.pools = map[ServiceDescriptor]*servicePool{}
+		}
+		scope.pools[descriptor] = pool
+	}
+	return pool
 }
 
-func (scope *defaultContainer) findDescriptorData(serviceType reflect.Type) *descriptorData {
-	for _, data := range scope.data {
-		if data.descriptor.ServiceType() == serviceType {
-			return data
+// closePools disposes the idle instances of the pools of the root container.
+// Instances returned afterwards are disposed right away.
+func (scope *defaultContainer) closePools() {
+	scope.mutex.Lock()
+	pools := scope.pools
+	scope.mutex.Unlock()
+
+	for descriptor, pool := range pools {
+		for _, instance := range pool.close() {
+			if instance.Disposable != nil {
+				scope.disposeInstance(descriptorInstance{descriptor: descriptor, disposable: instance.Disposable})
+			}
 		}
 	}
+}
+
+// poolStats implements poolStatsProvider
+func (scope *defaultContainer) poolStats(serviceType reflect.Type) (PoolStats, bool) {
+	descriptor := scope.describer.GetServiceDescriptor(serviceType)
+	if descriptor == nil || descriptor.Lifetime() != Pooled {
+		return PoolStats{}, false
+	}
+	return scope.root.pool(descriptor).snapshot(), true
+}
+
+// track registers a disposable to be disposed with the container. When the
+// container is already disposed, the disposable is disposed right away.
+func (scope *defaultContainer) track(descriptor ServiceDescriptor, disposable Disposable) error {
+	if disposable == nil || disposable == noopDisposableInstance {
+		return nil
+	}
+
+	entry := descriptorInstance{
+		descriptor: descriptor,
+		disposable: disposable,
+	}
+
+	scope.mutex.Lock()
+	if scope.disposed {
+		scope.mutex.Unlock()
+		scope.disposeInstance(entry)
+		return ErrServiceContainerDisposed
+	}
+	scope.disposables = append(scope.disposables, entry)
+	scope.mutex.Unlock()
 
 	return nil
 }
 
+func (scope *defaultContainer) disposeInstance(entry descriptorInstance) {
+	scope.guardDispose(entry.descriptor, entry.disposable.Dispose)
+}
+
+// guardDispose calls dispose, reporting a panic to the OnDisposeFailed hook.
+func (scope *defaultContainer) guardDispose(descriptor ServiceDescriptor, dispose func()) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if hook := scope.hooks.OnDisposeFailed; hook != nil {
+				hook(DisposeFailedEvent{
+					Descriptor: descriptor,
+					ScopeID:    scope.id,
+					Err:        recoveredError(recovered),
+				})
+			}
+		}
+	}()
+
+	dispose()
+}
+
+func (scope *defaultContainer) onResolved(descriptor ServiceDescriptor, cached bool) {
+	if hook := scope.hooks.OnResolved; hook != nil {
+		hook(ResolvedEvent{
+			Descriptor: descriptor,
+			ScopeID:    scope.id,
+			Cached:     cached,
+		})
+	}
+}
+
+func (scope *defaultContainer) scopeEvent() ScopeEvent {
+	event := ScopeEvent{ScopeID: scope.id}
+	if scope.parent != nil {
+		event.ParentID = scope.parent.id
+	}
+	return event
+}
+
 func newDefaultContainer(
 	describer ServiceDescriber,
-	descriptors []ServiceDescriptor,
-	parent ServiceProvider,
+	hooks ContainerHooks,
+	parent *defaultContainer,
 ) (*defaultContainer, error) {
 	if describer == nil {
 		return nil, ErrMissingServiceDescriber
 	}
 
-	return &defaultContainer{
-		data:   nil,
-		parent: parent,
-	}, nil
+	scope := &defaultContainer{
+		id:        atomic.AddUint64(&lastScopeID, 1),
+		describer: describer,
+		hooks:     hooks,
+		parent:    parent,
+		data:      map[ServiceDescriptor]*descriptorData{},
+	}
+
+	scope.root = scope
+	if parent != nil {
+		scope.root = parent.root
+		scope.rejectNil = parent.rejectNil
+	}
+
+	if hook := hooks.OnScopeCreated; hook != nil {
+		hook(scope.scopeEvent())
+	}
+
+	return scope, nil
+}
+
+// resolution is the ServiceProvider given to service factories. It carries
+// the chain of descriptors being instantiated by a top-level request.
+type resolution struct {
+	scope *defaultContainer
+	chain []ServiceDescriptor
+	// graph is shared by the resolutions of a top-level request
+	graph *resolutionGraph
+}
+
+// newResolution returns the resolution of a top-level request.
+func newResolution(scope *defaultContainer) *resolution {
+	return &resolution{scope: scope, graph: &resolutionGraph{}}
+}
+
+// resolutionGraph holds the per-resolution instances of a top-level request.
+type resolutionGraph struct {
+	mutex     sync.Mutex
+	instances map[graphKey]any
+}
+
+// graphKey identifies a per-resolution instance within a resolution graph.
+type graphKey struct {
+	descriptor ServiceDescriptor
+	scope      *defaultContainer
+}
+
+func (graph *resolutionGraph) get(key graphKey) (any, bool) {
+	graph.mutex.Lock()
+	defer graph.mutex.Unlock()
+	instance, ok := graph.instances[key]
+	return instance, ok
+}
+
+func (graph *resolutionGraph) set(key graphKey, instance any) {
+	graph.mutex.Lock()
+	defer graph.mutex.Unlock()
+	if graph.instances == nil {
+		graph.instances = map[graphKey]any{}
+	}
+	graph.instances[key] = instance
+}
+
+var _ ServiceProvider = (*resolution)(nil)
+var _ taggedServiceResolver = (*resolution)(nil)
+var _ poolStatsProvider = (*resolution)(nil)
+
+// GetService implements ServiceProvider
+func (res *resolution) GetService(serviceType reflect.Type) (any, error) {
+	return res.scope.resolve(res, serviceType)
+}
+
+// getServicesWithTag implements taggedServiceResolver
+func (res *resolution) getServicesWithTag(serviceType reflect.Type, tag string) (any, error) {
+	return res.scope.resolveWithTag(res, serviceType, tag)
+}
+
+// poolStats implements poolStatsProvider
+func (res *resolution) poolStats(serviceType reflect.Type) (PoolStats, bool) {
+	return res.scope.poolStats(serviceType)
+}
+
+// GetServiceInfo implements ServiceProvider
+func (res *resolution) GetServiceInfo(serviceType reflect.Type) ServiceInfo {
+	return res.scope.GetServiceInfo(serviceType)
+}
+
+// ServiceResolutionError is returned when a service cannot be resolved.
+// Chain holds the descriptors that were being instantiated when ServiceType failed.
+type ServiceResolutionError struct {
+	Chain       []ServiceDescriptor
+	ServiceType reflect.Type
+	Err         error
+}
+
+var _ error = (*ServiceResolutionError)(nil)
+
+func newServiceResolutionError(
+	chain []ServiceDescriptor,
+	serviceType reflect.Type,
+	err error,
+) *ServiceResolutionError {
+	return &ServiceResolutionError{
+		Chain:       cloneSlice(chain),
+		ServiceType: serviceType,
+		Err:         err,
+	}
+}
+
+// wrapServiceResolutionError keeps errors already carrying a resolution chain,
+// as the innermost chain is the most precise one.
+func wrapServiceResolutionError(
+	chain []ServiceDescriptor,
+	serviceType reflect.Type,
+	err error,
+) error {
+	var resolutionErr *ServiceResolutionError
+	if errors.As(err, &resolutionErr) {
+		return err
+	}
+	return newServiceResolutionError(chain, serviceType, err)
+}
+
+func (err *ServiceResolutionError) Error() string {
+	if len(err.Chain) == 0 {
+		return fmt.Sprintf("service request %s fails: %s", err.ServiceType, err.Err)
+	}
+	return fmt.Sprintf(
+		"service request %s ==> %s fails: %s",
+		requestChainString(err.Chain),
+		err.ServiceType,
+		err.Err)
+}
+
+func (err *ServiceResolutionError) Unwrap() error {
+	return err.Err
+}
+
+func recoveredError(recovered any) error {
+	if err, ok := recovered.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", recovered)
 }
@@ -1,60 +1,364 @@
 package di
 
-// import (
-// 	"testing"
-
-// 	"github.com/stretchr/testify/assert"
-// )
-
-// func TestNewSingletonScope_Empty(t *testing.T) {
-// 	descriptors := []ServiceDescriptor{}
-// 	scope, err := newDefaultContainer(descriptors, nil)
-
-// 	assert.NoError(t, err)
-// 	assert.NotNil(t, scope)
-// 	assert.False(t, scope.IsDisposed())
-
-// 	provider := scope.Provider()
-// 	assert.NotNil(t, provider)
-
-// 	serviceInfo := provider.GetServiceInfo(typeOfTestServiceInterface)
-// 	assert.Equal(t, UnknownLifetime, serviceInfo.Lifetime)
-// 	assert.False(t, serviceInfo.IsInstantiated)
-// 	assert.Equal(t, typeOfTestServiceInterface, serviceInfo.ServiceType)
-
-// 	scope.Dispose()
-// 	assert.True(t, scope.IsDisposed())
-// }
-
-// func TestNewSingletonScope_WithStructToInterface(t *testing.T) {
-// 	descriptor, _ := NewSingletonStruct[testServiceInterface, testServiceStruct]()
-// 	descriptors := []ServiceDescriptor{
-// 		descriptor,
-// 	}
-// 	scope, err := newDefaultContainer(descriptors, nil)
-
-// 	assert.NoError(t, err)
-// 	assert.NotNil(t, scope)
-// 	assert.False(t, scope.IsDisposed())
-
-// 	provider := scope.Provider()
-// 	assert.NotNil(t, provider)
-
-// 	serviceInfo := provider.GetServiceInfo(typeOfTestServiceInterface)
-// 	assert.Equal(t, Singleton, serviceInfo.Lifetime)
-// 	assert.False(t, serviceInfo.IsInstantiated)
-// 	assert.Equal(t, typeOfTestServiceInterface, serviceInfo.ServiceType)
-
-// 	service, err := provider.GetService(typeOfTestServiceInterface)
-// 	assert.NoError(t, err)
-// 	assert.NotNil(t, service)
-// 	assert.IsType(t, &testServiceStruct{}, service)
-
-// 	scope.Dispose()
-// 	assert.True(t, scope.IsDisposed())
-
-// 	serviceInfo = provider.GetServiceInfo(typeOfTestServiceInterface)
-// 	assert.Equal(t, UnknownLifetime, serviceInfo.Lifetime)
-// 	assert.False(t, serviceInfo.IsInstantiated)
-// 	assert.Equal(t, typeOfTestServiceInterface, serviceInfo.ServiceType)
-// }
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestContainer(t *testing.T, descriptors ...ServiceDescriptor) ServiceContainer {
+	container, err := NewServiceCollection().AddRange(descriptors...).Build()
+	assert.NoError(t, err)
+	assert.NotNil(t, container)
+	return container
+}
+
+func TestNewSingletonScope_Empty(t *testing.T) {
+	scope := newTestContainer(t)
+	assert.False(t, scope.IsDisposed())
+	assert.False(t, scope.IsScoped())
+
+	provider := scope.Provider()
+	assert.NotNil(t, provider)
+
+	serviceInfo := provider.GetServiceInfo(typeOfTestServiceInterface)
+	assert.Equal(t, UnknownLifetime, serviceInfo.Lifetime)
+	assert.False(t, serviceInfo.IsInstantiated)
+	assert.Equal(t, typeOfTestServiceInterface, serviceInfo.ServiceType)
+
+	service, err := provider.GetService(typeOfTestServiceInterface)
+	assert.Nil(t, service)
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+
+	scope.Dispose()
+	assert.True(t, scope.IsDisposed())
+}
+
+func TestNewSingletonScope_WithStructToInterface(t *testing.T) {
+	descriptor, _ := NewSingletonStruct[testServiceInterface, testServiceStruct]()
+	scope := newTestContainer(t, descriptor)
+
+	provider := scope.Provider()
+
+	serviceInfo := provider.GetServiceInfo(typeOfTestServiceInterface)
+	assert.Equal(t, Singleton, serviceInfo.Lifetime)
+	assert.False(t, serviceInfo.IsInstantiated)
+	assert.Equal(t, typeOfTestServiceInterface, serviceInfo.ServiceType)
+
+	service, err := provider.GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+	assert.NotNil(t, service)
+	assert.IsType(t, &testServiceStruct{}, service)
+
+	serviceInfo = provider.GetServiceInfo(typeOfTestServiceInterface)
+	assert.True(t, serviceInfo.IsInstantiated)
+
+	again, err := provider.GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+	assert.Same(t, service, again)
+
+	scope.Dispose()
+	assert.True(t, scope.IsDisposed())
+
+	serviceInfo = provider.GetServiceInfo(typeOfTestServiceInterface)
+	assert.Equal(t, UnknownLifetime, serviceInfo.Lifetime)
+	assert.False(t, serviceInfo.IsInstantiated)
+	assert.Equal(t, typeOfTestServiceInterface, serviceInfo.ServiceType)
+
+	_, err = provider.GetService(typeOfTestServiceInterface)
+	assert.Equal(t, ErrServiceContainerDisposed, err)
+}
+
+func TestDefaultContainer_SingletonSharedWithScopes(t *testing.T) {
+	descriptor, _ := NewSingletonStruct[testServiceInterface, testServiceStruct]()
+	root := newTestContainer(t, descriptor)
+
+	scope, err := root.CreateScope()
+	assert.NoError(t, err)
+	assert.True(t, scope.IsScoped())
+
+	fromScope, err := scope.Provider().GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+	fromRoot, err := root.Provider().GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+	assert.Same(t, fromRoot, fromScope)
+}
+
+func TestDefaultContainer_ScopedPerScope(t *testing.T) {
+	descriptor, _ := NewScopedStruct[testServiceInterface, testStructWithFields]()
+	root := newTestContainer(t, descriptor,
+		NewSingletonFactory[int](func(ServiceProvider) (any, error) { return 42, nil }),
+		NewSingletonFactory[string](func(ServiceProvider) (any, error) { return "hello", nil }))
+
+	_, err := root.Provider().GetService(typeOfTestServiceInterface)
+	assert.ErrorIs(t, err, ErrScopedServiceFromRoot)
+
+	scope1, _ := root.CreateScope()
+	scope2, _ := root.CreateScope()
+
+	service1, err := scope1.Provider().GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+	again1, _ := scope1.Provider().GetService(typeOfTestServiceInterface)
+	service2, err := scope2.Provider().GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+
+	assert.Same(t, service1, again1)
+	assert.NotSame(t, service1, service2)
+	assert.Equal(t, &testStructWithFields{Field1: 42, Field2: "hello", Field3: []bool{}}, service1)
+}
+
+func TestDefaultContainer_TransientAlwaysNew(t *testing.T) {
+	root := newTestContainer(t,
+		NewTransientFactory[testServiceInterface](func(ServiceProvider) (any, error) {
+			return &testStructWithFields{}, nil
+		}))
+
+	service1, err := root.Provider().GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+	service2, err := root.Provider().GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+	assert.NotSame(t, service1, service2)
+}
+
+func TestDefaultContainer_SliceResolvesAllDescriptors(t *testing.T) {
+	descriptor1, _ := NewInstance[testServiceInterface](&testStructWithFields{Field1: 1})
+	descriptor2, _ := NewInstance[testServiceInterface](&testStructWithFields{Field1: 2})
+	root := newTestContainer(t, descriptor1, descriptor2)
+
+	services, err := root.Provider().GetService(typeOf[[]testServiceInterface]())
+	assert.NoError(t, err)
+	assert.Equal(t, []testServiceInterface{
+		&testStructWithFields{Field1: 1},
+		&testStructWithFields{Field1: 2},
+	}, services)
+
+	single, err := root.Provider().GetService(typeOfTestServiceInterface)
+	assert.NoError(t, err)
+	assert.Equal(t, &testStructWithFields{Field1: 2}, single)
+}
+
+type testDisposeRecorder struct {
+	name     string
+	disposed *[]string
+}
+
+func (recorder *testDisposeRecorder) Dispose() {
+	*recorder.disposed = append(*recorder.disposed, recorder.name)
+}
+
+type testDisposeRecorderDependent struct {
+	Dependency *testDisposeRecorder
+}
+
+func (dependent *testDisposeRecorderDependent) Dispose() {
+	*dependent.Dependency.disposed = append(*dependent.Dependency.disposed, "dependent")
+}
+
+func TestDefaultContainer_DisposesInReverseOrder(t *testing.T) {
+	var disposed []string
+	dependent, _ := NewScopedStructPtr[testDisposeRecorderDependent]()
+	root := newTestContainer(t,
+		NewSingletonFactory[*testDisposeRecorder](func(ServiceProvider) (any, error) {
+			return &testDisposeRecorder{name: "dependency", disposed: &disposed}, nil
+		}),
+		dependent)
+	scope, _ := root.CreateScope()
+
+	_, err := scope.Provider().GetService(typeOf[*testDisposeRecorderDependent]())
+	assert.NoError(t, err)
+
+	scope.Dispose()
+	assert.Equal(t, []string{"dependent"}, disposed)
+	root.Dispose()
+	assert.Equal(t, []string{"dependent", "dependency"}, disposed)
+	root.Dispose()
+	assert.Equal(t, []string{"dependent", "dependency"}, disposed)
+}
+
+var errTestFactory = errors.New("test factory")
+
+func TestDefaultContainer_FailureReportsDependencyPath(t *testing.T) {
+	withoutCallSites(t)
+	dependent, _ := NewSingletonStructPtr[testStructWithDependency]()
+	root := newTestContainer(t,
+		dependent,
+		NewSingletonFactory[testServiceInterface](func(ServiceProvider) (any, error) {
+			return nil, errTestFactory
+		}))
+
+	_, err := root.Provider().GetService(typeOf[*testStructWithDependency]())
+	assert.ErrorIs(t, err, errTestFactory)
+	var resolutionErr *ServiceResolutionError
+	assert.ErrorAs(t, err, &resolutionErr)
+	assert.Equal(t, []ServiceDescriptor{dependent}, resolutionErr.Chain)
+	assert.Equal(t, typeOfTestServiceInterface, resolutionErr.ServiceType)
+	assert.Equal(t,
+		"service request [Singleton] *di.testStructWithDependency ==> di.testServiceInterface fails: test factory",
+		err.Error())
+}
+
+type testCircularA struct {
+	B *testCircularB
+}
+
+type testCircularB struct {
+	A *testCircularA
+}
+
+func TestDefaultContainer_CircularDependency(t *testing.T) {
+	descriptorA, _ := NewTransientStructPtr[testCircularA]()
+	descriptorB, _ := NewTransientStructPtr[testCircularB]()
+	root := newTestContainer(t, descriptorA, descriptorB)
+
+	_, err := root.Provider().GetService(typeOf[*testCircularA]())
+	assert.ErrorIs(t, err, ErrCircularDependency)
+}
+
+func TestDefaultContainer_CreateScopeOnDisposed(t *testing.T) {
+	root := newTestContainer(t)
+	root.Dispose()
+
+	scope, err := root.CreateScope()
+	assert.Nil(t, scope)
+	assert.Equal(t, ErrServiceContainerDisposed, err)
+}
+
+func TestNewDefaultContainer_WithoutDescriber(t *testing.T) {
+	container, err := newDefaultContainer(nil, ContainerHooks{}, nil)
+	assert.Nil(t, container)
+	assert.Equal(t, ErrMissingServiceDescriber, err)
+}
+
+func TestDefaultContainer_ResolvesOwningContainer(t *testing.T) {
+	type singletonHolder struct{ Container ServiceContainer }
+	type scopedHolder struct{ Container ServiceContainer }
+	singletonDescriptor, err := NewSingletonStructPtr[singletonHolder]()
+	assert.NoError(t, err)
+	scopedDescriptor, err := NewScopedStructPtr[scopedHolder]()
+	assert.NoError(t, err)
+	container := newTestContainer(t, singletonDescriptor, scopedDescriptor)
+	defer container.Dispose()
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+
+	singleton, err := GetService[*singletonHolder](scope.Provider())
+	assert.NoError(t, err)
+	assert.Same(t, container, singleton.Container)
+
+	scoped, err := GetService[*scopedHolder](scope.Provider())
+	assert.NoError(t, err)
+	assert.Same(t, scope, scoped.Container)
+}
+
+type testUnitOfWorkRepo struct {
+	Work *testPerResolutionWork
+}
+
+type testUnitOfWorkHandler struct {
+	Orders    *testUnitOfWorkRepo
+	Customers *testUnitOfWorkCustomers
+}
+
+type testUnitOfWorkCustomers struct {
+	Work *testPerResolutionWork
+}
+
+type testPerResolutionWork struct {
+	id       int
+	disposed *[]int
+}
+
+func (work *testPerResolutionWork) Dispose() {
+	*work.disposed = append(*work.disposed, work.id)
+}
+
+func TestDefaultContainer_PerResolution(t *testing.T) {
+	var disposed []int
+	created := 0
+	descriptor1 := NewPerResolutionFactory[*testPerResolutionWork](func(ServiceProvider) (any, error) {
+		created++
+		return &testPerResolutionWork{id: created, disposed: &disposed}, nil
+	})
+	descriptor2, _ := NewTransientStructPtr[testUnitOfWorkRepo]()
+	descriptor3, _ := NewScopedStructPtr[testUnitOfWorkCustomers]()
+	descriptor4, _ := NewTransientStructPtr[testUnitOfWorkHandler]()
+	container := newTestContainer(t, descriptor1, descriptor2, descriptor3, descriptor4)
+	defer container.Dispose()
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+
+	handler, err := GetService[*testUnitOfWorkHandler](scope.Provider())
+	assert.NoError(t, err)
+	assert.Same(t, handler.Orders.Work, handler.Customers.Work)
+	assert.Equal(t, 1, handler.Orders.Work.id)
+
+	other, err := GetService[*testUnitOfWorkHandler](scope.Provider())
+	assert.NoError(t, err)
+	assert.NotSame(t, handler.Orders.Work, other.Orders.Work)
+	assert.Same(t, handler.Customers, other.Customers)
+	assert.Equal(t, 2, other.Orders.Work.id)
+
+	work, err := GetService[*testPerResolutionWork](scope.Provider())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, work.id)
+
+	scope.Dispose()
+	assert.Equal(t, []int{3, 2, 1}, disposed)
+}
+
+func TestDefaultContainer_PerResolutionNotSharedWithSingletons(t *testing.T) {
+	type singletonRepo struct{ Work *testPerResolutionWork }
+	type mixedHandler struct {
+		Customers *testUnitOfWorkCustomers
+		Singleton *singletonRepo
+	}
+	var disposed []int
+	created := 0
+	descriptor1 := NewPerResolutionFactory[*testPerResolutionWork](func(ServiceProvider) (any, error) {
+		created++
+		return &testPerResolutionWork{id: created, disposed: &disposed}, nil
+	})
+	descriptor2, _ := NewScopedStructPtr[testUnitOfWorkCustomers]()
+	descriptor3, _ := NewSingletonStructPtr[singletonRepo]()
+	descriptor4, _ := NewTransientStructPtr[mixedHandler]()
+	container := newTestContainer(t, descriptor1, descriptor2, descriptor3, descriptor4)
+	defer container.Dispose()
+
+	scope, err := container.CreateScope()
+	assert.NoError(t, err)
+
+	resolved, err := GetService[*mixedHandler](scope.Provider())
+	assert.NoError(t, err)
+	assert.NotSame(t, resolved.Customers.Work, resolved.Singleton.Work)
+
+	scope.Dispose()
+	assert.Equal(t, []int{1}, disposed)
+	assert.Equal(t, 2, resolved.Singleton.Work.id)
+}
+
+func TestDefaultContainer_PerResolutionInOwned(t *testing.T) {
+	type ownedHandler struct {
+		Orders *testUnitOfWorkRepo
+		Owned  Owned[*testUnitOfWorkRepo]
+	}
+	var disposed []int
+	created := 0
+	descriptor1 := NewPerResolutionFactory[*testPerResolutionWork](func(ServiceProvider) (any, error) {
+		created++
+		return &testPerResolutionWork{id: created, disposed: &disposed}, nil
+	})
+	descriptor2, _ := NewTransientStructPtr[testUnitOfWorkRepo]()
+	descriptor3, _ := NewTransientStructPtr[ownedHandler]()
+	container := newTestContainer(t, descriptor1, descriptor2, descriptor3)
+	defer container.Dispose()
+
+	resolved, err := GetService[*ownedHandler](container.Provider())
+	assert.NoError(t, err)
+	assert.NotSame(t, resolved.Orders.Work, resolved.Owned.Value.Work)
+
+	resolved.Owned.Dispose()
+	assert.Equal(t, []int{resolved.Owned.Value.Work.id}, disposed)
+}
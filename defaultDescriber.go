@@ -50,10 +50,26 @@ func searchDescriptors(descriptors []ServiceDescriptor, serviceType reflect.Type
 
 // newDefaultDescriber creates a new default service describer
 func newDefaultDescriber(descriptors []ServiceDescriptor) (*defaultDescriber, error) {
-	if err := validateDescriptors(descriptors); err != nil {
+	return newDefaultDescriberWith(descriptors, validationRules{})
+}
+
+// newDefaultDescriberWith creates a new default service describer, validating
+// the descriptors with the given rules
+func newDefaultDescriberWith(descriptors []ServiceDescriptor, rules validationRules) (*defaultDescriber, error) {
+	descriptors, err := sortDescriptors(descriptors)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateLifetimes(descriptors); err != nil {
 		return nil, err
 	}
 
+	if !rules.skip {
+		if err := validateDescriptors(descriptors, rules); err != nil {
+			return nil, err
+		}
+	}
+
 	return &defaultDescriber{
 		descriptors: cloneSlice(descriptors),
 	}, nil
@@ -76,6 +92,13 @@ func (err *ServiceDependencyError) Error() string {
 	return sb.String()
 }
 
+// validationRules tells how descriptors are validated
+type validationRules struct {
+	skip             bool
+	skipScopes       bool
+	strictDuplicates bool
+}
+
 type validatedDescriptor struct {
 	descriptor            ServiceDescriptor
 	validatedForRecurse   bool
@@ -85,6 +108,7 @@ type validatedDescriptor struct {
 
 func validateDescriptors(
 	descriptors []ServiceDescriptor,
+	rules validationRules,
 ) error {
 	validations := mapSlice(
 		descriptors,
@@ -95,7 +119,10 @@ func validateDescriptors(
 		},
 	)
 
-	messages := validateDescriptorsAux(validations)
+	messages := validateDescriptorsAux(validations, rules)
+	if rules.strictDuplicates {
+		messages = append(messages, validateDuplicates(descriptors)...)
+	}
 
 	if len(messages) > 0 {
 		messages = distinctSlice(messages)
@@ -108,6 +135,7 @@ func validateDescriptors(
 
 func validateDescriptorsAux(
 	validations []*validatedDescriptor,
+	rules validationRules,
 ) []string {
 	var messages []string
 
@@ -120,6 +148,7 @@ func validateDescriptorsAux(
 				validation,
 				validations,
 				recurse,
+				rules,
 			)
 			messages = append(messages, moreErrors...)
 		}
@@ -137,6 +166,7 @@ func validateDescriptor(
 	validation *validatedDescriptor,
 	validations []*validatedDescriptor,
 	recurse bool,
+	rules validationRules,
 ) []string {
 	if lifetime == Scoped {
 		if validation.validatedForScoped && validation.validatedForRecurse == recurse {
@@ -159,6 +189,21 @@ func validateDescriptor(
 	requirements := validation.descriptor.Factory().Requirements()
 
 	for _, requirement := range requirements {
+		if serviceType, isOwned := unwrapOwned(requirement); isOwned {
+			// Owned services are resolved from their own scope
+			owned := newOwnedDescriptor(requirement, serviceType)
+			messages = append(
+				messages,
+				validateDescriptor(
+					Scoped,
+					append(requestChain, owned),
+					&validatedDescriptor{descriptor: owned},
+					validations,
+					recurse,
+					rules)...)
+			continue
+		}
+		requirement = unwrapMetaRequirement(requirement)
 		if requirement.Kind() == reflect.Slice {
 			for _, current := range validations {
 				if current.descriptor.ServiceType() == requirement.Elem() {
@@ -168,7 +213,8 @@ func validateDescriptor(
 						validateRequirement(
 							lifetime,
 							current.descriptor,
-							requestChain)...)
+							requestChain,
+							rules)...)
 					// Validate the requirement's requirements from the current lifetime
 					if recurse {
 						messages = append(
@@ -178,7 +224,8 @@ func validateDescriptor(
 								append(requestChain, current.descriptor),
 								current,
 								validations,
-								recurse)...)
+								recurse,
+								rules)...)
 					}
 				}
 			}
@@ -192,7 +239,8 @@ func validateDescriptor(
 						validateRequirement(
 							lifetime,
 							current.descriptor,
-							requestChain)...)
+							requestChain,
+							rules)...)
 					// Validate the requirement's requirements from the current lifetime
 					if recurse {
 						messages = append(
@@ -202,12 +250,18 @@ func validateDescriptor(
 								append(requestChain, current.descriptor),
 								current,
 								validations,
-								recurse)...)
+								recurse,
+								rules)...)
 					}
 					return messages
 				}
 			}
 
+			if requirement == typeOfServiceContainer {
+				// The container is always available
+				continue
+			}
+
 			messages = append(
 				messages,
 				fmt.Sprintf(
@@ -224,10 +278,11 @@ func validateRequirement(
 	lifetime Lifetime,
 	requirementDescriptor ServiceDescriptor,
 	requestChain []ServiceDescriptor,
+	rules validationRules,
 ) []string {
 	var messages []string
 
-	if !isValidRequirement(lifetime, requirementDescriptor) {
+	if !rules.skipScopes && !isValidRequirement(lifetime, requirementDescriptor) {
 		messages = append(
 			messages,
 			fmt.Sprintf(
@@ -239,12 +294,68 @@ func validateRequirement(
 	return messages
 }
 
+// validateLifetimes fails on the descriptors of lifetimes neither built in
+// nor registered with RegisterLifetime, which could never be resolved.
+func validateLifetimes(descriptors []ServiceDescriptor) error {
+	var errs []error
+	for _, descriptor := range descriptors {
+		if !isKnownLifetime(descriptor.Lifetime()) {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownLifetime, descriptor))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateDuplicates reports the service types registered more than once,
+// and required as a single service, since only the last registration is used.
+func validateDuplicates(descriptors []ServiceDescriptor) []string {
+	var messages []string
+
+	for _, descriptor := range descriptors {
+		for _, requirement := range descriptor.Factory().Requirements() {
+			requirement = unwrapMetaRequirement(requirement)
+			if requirement.Kind() == reflect.Slice {
+				continue
+			}
+			if count := len(searchDescriptors(descriptors, requirement)); count > 1 {
+				messages = append(
+					messages,
+					fmt.Sprintf(
+						"service request %s =(ambiguous)=> %s fails: registered %d times",
+						descriptor,
+						requirement,
+						count))
+			}
+		}
+	}
+
+	return messages
+}
+
 func isValidRequirement(
 	lifetime Lifetime,
 	requirementDescriptor ServiceDescriptor,
 ) bool {
-	if lifetime == Singleton && requirementDescriptor.Lifetime() == Scoped {
-		return false
+	switch requirementDescriptor.Lifetime() {
+	case Scoped:
+		// Singleton and pooled instances outlive the scope
+		if lifetime == Singleton || lifetime == Pooled {
+			return false
+		}
+	case PerResolution:
+		// Pooled instances outlive the resolution sharing the instance
+		if lifetime == Pooled {
+			return false
+		}
+	default:
+		// Singleton and pooled instances would capture one instance for good
+		custom, ok := lookupLifetime(requirementDescriptor.Lifetime())
+		if ok && !custom.rules.Capturable && (lifetime == Singleton || lifetime == Pooled) {
+			return false
+		}
+	}
+	if custom, ok := lookupLifetime(lifetime); ok && custom.rules.CanRequire != nil {
+		return custom.rules.CanRequire(requirementDescriptor.Lifetime())
 	}
 
 	return true
@@ -38,6 +38,7 @@ type testStructWithOtherDependency struct {
 }
 
 func TestNewDefaultDescriber_WithMissingDependency(t *testing.T) {
+	withoutCallSites(t)
 	descriptor, _ := NewSingletonStructPtr[testStructWithDependency]()
 	descriptors := []ServiceDescriptor{descriptor}
 
@@ -85,6 +86,7 @@ func TestNewDefaultDescriber_SingletonToSingletonSlice(t *testing.T) {
 }
 
 func TestNewDefaultDescriber_SingletonToScoped(t *testing.T) {
+	withoutCallSites(t)
 	descriptor1, _ := NewSingletonStructPtr[testStructWithDependency]()
 	descriptor2, _ := NewScopedStruct[testServiceInterface, testServiceStruct]()
 	descriptors := []ServiceDescriptor{descriptor1, descriptor2}
@@ -96,6 +98,7 @@ func TestNewDefaultDescriber_SingletonToScoped(t *testing.T) {
 }
 
 func TestNewDefaultDescriber_SingletonToScopedSlice(t *testing.T) {
+	withoutCallSites(t)
 	descriptor1, _ := NewSingletonStructPtr[testStructWithDependencySlice]()
 	descriptor2, _ := NewScopedStruct[testServiceInterface, testServiceStruct]()
 	descriptors := []ServiceDescriptor{descriptor1, descriptor2}
@@ -247,6 +250,7 @@ func TestNewDefaultDescriber_TransientToTransientSlice(t *testing.T) {
 }
 
 func TestNewDefaultDescriber_SingletonToTransientToScoped(t *testing.T) {
+	withoutCallSites(t)
 	descriptor1, _ := NewSingletonStructPtr[testStructWithOtherDependency]()
 	descriptor2, _ := NewTransientStructPtr[testStructWithDependency]()
 	descriptor3, _ := NewScopedStruct[testServiceInterface, testServiceStruct]()
@@ -259,7 +263,80 @@ func TestNewDefaultDescriber_SingletonToTransientToScoped(t *testing.T) {
 		"[Singleton] *di.testStructWithOtherDependency ==> [Transient] *di.testStructWithDependency =(invalid)=> [Scoped] di.testServiceInterface")
 }
 
+func TestNewDefaultDescriber_SingletonToPerResolutionToScoped(t *testing.T) {
+	withoutCallSites(t)
+	descriptor1, _ := NewSingletonStructPtr[testStructWithOtherDependency]()
+	descriptor2, _ := NewPerResolutionStructPtr[testStructWithDependency]()
+	descriptor3, _ := NewScopedStruct[testServiceInterface, testServiceStruct]()
+	descriptors := []ServiceDescriptor{descriptor1, descriptor2, descriptor3}
+
+	describer, err := newDefaultDescriber(descriptors)
+	assert.Nil(t, describer)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(),
+		"[Singleton] *di.testStructWithOtherDependency ==> [PerResolution] *di.testStructWithDependency =(invalid)=> [Scoped] di.testServiceInterface")
+}
+
+func TestNewDefaultDescriber_ScopedToPerResolutionToScoped(t *testing.T) {
+	descriptor1, _ := NewScopedStructPtr[testStructWithOtherDependency]()
+	descriptor2, _ := NewPerResolutionStructPtr[testStructWithDependency]()
+	descriptor3, _ := NewScopedStruct[testServiceInterface, testServiceStruct]()
+	descriptors := []ServiceDescriptor{descriptor1, descriptor2, descriptor3}
+
+	describer, err := newDefaultDescriber(descriptors)
+	assert.NoError(t, err)
+	assert.NotNil(t, describer)
+}
+
+func TestNewDefaultDescriber_SingletonToPerResolution(t *testing.T) {
+	descriptor1, _ := NewSingletonStructPtr[testStructWithDependency]()
+	descriptor2, _ := NewPerResolutionStruct[testServiceInterface, testServiceStruct]()
+	descriptors := []ServiceDescriptor{descriptor1, descriptor2}
+
+	describer, err := newDefaultDescriber(descriptors)
+	assert.NoError(t, err)
+	assert.NotNil(t, describer)
+}
+
+func TestNewDefaultDescriber_PooledToPerResolution(t *testing.T) {
+	withoutCallSites(t)
+	descriptor1, _ := NewPooledStructPtr[testStructWithDependency]()
+	descriptor2, _ := NewPerResolutionStruct[testServiceInterface, testServiceStruct]()
+	descriptors := []ServiceDescriptor{descriptor1, descriptor2}
+
+	describer, err := newDefaultDescriber(descriptors)
+	assert.Nil(t, describer)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(),
+		"[Pooled] *di.testStructWithDependency =(invalid)=> [PerResolution] di.testServiceInterface")
+}
+
+func TestNewDefaultDescriber_PooledToTransientToPerResolution(t *testing.T) {
+	withoutCallSites(t)
+	descriptor1, _ := NewPooledStructPtr[testStructWithOtherDependency]()
+	descriptor2, _ := NewTransientStructPtr[testStructWithDependency]()
+	descriptor3, _ := NewPerResolutionStruct[testServiceInterface, testServiceStruct]()
+	descriptors := []ServiceDescriptor{descriptor1, descriptor2, descriptor3}
+
+	describer, err := newDefaultDescriber(descriptors)
+	assert.Nil(t, describer)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(),
+		"[Pooled] *di.testStructWithOtherDependency ==> [Transient] *di.testStructWithDependency =(invalid)=> [PerResolution] di.testServiceInterface")
+}
+
+func TestNewDefaultDescriber_PerResolutionToPerResolution(t *testing.T) {
+	descriptor1, _ := NewPerResolutionStructPtr[testStructWithDependency]()
+	descriptor2, _ := NewPerResolutionStruct[testServiceInterface, testServiceStruct]()
+	descriptors := []ServiceDescriptor{descriptor1, descriptor2}
+
+	describer, err := newDefaultDescriber(descriptors)
+	assert.NoError(t, err)
+	assert.NotNil(t, describer)
+}
+
 func TestNewDefaultDescriber_SingletonToTransientToScopedSlice(t *testing.T) {
+	withoutCallSites(t)
 	descriptor1, _ := NewSingletonStructPtr[testStructWithOtherDependencySlice]()
 	descriptor2, _ := NewTransientStructPtr[testStructWithDependencySlice]()
 	descriptor3, _ := NewScopedStruct[testServiceInterface, testServiceStruct]()
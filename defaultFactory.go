@@ -10,9 +10,10 @@ import (
 )
 
 type defaultFactory struct {
-	factoryFunc  ServiceFactoryFunc
-	requirements []reflect.Type
-	displayName  string
+	factoryFunc    ServiceFactoryFunc
+	requirements   []reflect.Type
+	displayName    string
+	implementation any
 }
 
 // ServiceFactory interface implementation
@@ -67,6 +68,31 @@ func NewFactoryWith(
 		toServiceInstanceFactoryFunc(factoryFunc))
 }
 
+// withImplementation sets what the factory instantiates, used to compare
+// factories. See implementationOf.
+func withImplementation(factory ServiceFactory, implementation any) ServiceFactory {
+	factory.(*defaultFactory).implementation = implementation
+	return factory
+}
+
+// implementationOf returns what identifies the implementation of a factory:
+// the struct type, instance or function it was created from, or else its
+// display name.
+func implementationOf(factory ServiceFactory) any {
+	if fact, ok := factory.(*defaultFactory); ok && fact.implementation != nil {
+		return fact.implementation
+	}
+	return factory.DisplayName()
+}
+
+// functionImplementation identifies a function by its full name, shared by
+// every closure of a same function literal.
+type functionImplementation string
+
+func getFunctionImplementation(function any) functionImplementation {
+	return functionImplementation(runtime.FuncForPC(reflect.ValueOf(function).Pointer()).Name())
+}
+
 func getFunctionName(function any) string {
 	valueOfFunc := reflect.ValueOf(function)
 	fullName := runtime.FuncForPC(valueOfFunc.Pointer()).Name()
@@ -80,10 +106,12 @@ func getFunctionName(function any) string {
 // returns:
 // 	the new service factory
 func NewServiceInstanceFactory(factoryFunc ServiceFactoryFunc) ServiceFactory {
-	return NewServiceInstanceFactoryWith(
-		getFunctionName(factoryFunc),
-		[]reflect.Type{},
-		factoryFunc)
+	return withImplementation(
+		NewServiceInstanceFactoryWith(
+			getFunctionName(factoryFunc),
+			[]reflect.Type{},
+			factoryFunc),
+		getFunctionImplementation(factoryFunc))
 }
 
 // NewFactory creates a new service factory from the given factory function.
@@ -92,10 +120,12 @@ func NewServiceInstanceFactory(factoryFunc ServiceFactoryFunc) ServiceFactory {
 // returns:
 // 	the new service factory
 func NewFactory(factoryFunc SimpleServiceFactoryFunc) ServiceFactory {
-	return NewServiceInstanceFactoryWith(
-		getFunctionName(factoryFunc),
-		[]reflect.Type{},
-		toServiceInstanceFactoryFunc(factoryFunc))
+	return withImplementation(
+		NewServiceInstanceFactoryWith(
+			getFunctionName(factoryFunc),
+			[]reflect.Type{},
+			toServiceInstanceFactoryFunc(factoryFunc)),
+		getFunctionImplementation(factoryFunc))
 }
 
 // NewStructFactoryForType creates a new service factory from the given struct type.
@@ -114,7 +144,9 @@ func NewStructFactoryForType(structType reflect.Type) (ServiceFactory, error) {
 			return structType.Field(i).Type
 		})
 
-	return NewServiceInstanceFactoryWith(displayName, requirements, factory), nil
+	return withImplementation(
+		NewServiceInstanceFactoryWith(displayName, requirements, factory),
+		structType), nil
 }
 
 // NewStructFactory creates a new service factory from the given struct type.
@@ -142,10 +174,12 @@ func NewFuncFactory(function any) (ServiceFactory, error) {
 			return funcType.In(i)
 		})
 
-	return NewServiceInstanceFactoryWith(
-		getFunctionName(function),
-		requirements, 
-		factory), nil
+	return withImplementation(
+		NewServiceInstanceFactoryWith(
+			getFunctionName(function),
+			requirements,
+			factory),
+		getFunctionImplementation(function)), nil
 }
 
 // newInstanceFactoryWith creates a new service factory from the given instance.
@@ -168,7 +202,9 @@ func newInstanceFactoryWith(displayName string, instance any) (ServiceFactory, e
 		return instance, nil
 	}
 
-	return NewFactoryWith(displayName, []reflect.Type{}, factory), nil
+	return withImplementation(
+		NewFactoryWith(displayName, []reflect.Type{}, factory),
+		instance), nil
 }
 
 func getInstanceName(instance any) string {
@@ -9,6 +9,7 @@ type descriptor struct {
 	serviceType reflect.Type
 	lifetime    Lifetime
 	factory     ServiceFactory
+	callSite    CallSite
 }
 
 var _ ServiceDescriptor = (*descriptor)(nil)
@@ -30,8 +31,25 @@ func (desc *descriptor) ServiceType() reflect.Type {
 	return desc.serviceType
 }
 
+// CallSite implements ServiceDescriptor.CallSite to return where the descriptor was created.
+func (desc *descriptor) CallSite() CallSite {
+	return desc.callSite
+}
+
+// Metadata implements ServiceDescriptor.Metadata to return no metadata.
+func (desc *descriptor) Metadata() Metadata {
+	return Metadata{}
+}
+
 // String implements ServiceDescriptor.String to return the string representation of the service descriptor.
 func (desc *descriptor) String() string {
+	if desc.callSite.IsKnown() {
+		return fmt.Sprintf(
+			"[%s] %s (%s)",
+			desc.lifetime,
+			desc.serviceType,
+			desc.callSite)
+	}
 	return fmt.Sprintf(
 		"[%s] %s",
 		desc.lifetime,
@@ -52,6 +70,7 @@ func NewDescriptorForType(serviceType reflect.Type, lifetime Lifetime, factory S
 		serviceType: serviceType,
 		lifetime:    lifetime,
 		factory:     factory,
+		callSite:    captureCallSite(),
 	}
 }
 
@@ -198,6 +217,98 @@ func NewTransientStructPtr[Impl any]() (ServiceDescriptor, error) {
 	return NewTransientStruct[*Impl, Impl]()
 }
 
+// NewPooledServiceFactoryForType creates a new pooled service descriptor for the given service type.
+func NewPooledServiceFactoryForType(serviceType reflect.Type, factory ServiceFactory) ServiceDescriptor {
+	return NewDescriptorForType(serviceType, Pooled, factory)
+}
+
+// NewPooledServiceFactory creates a new pooled service descriptor for the given service type.
+func NewPooledServiceFactory[T any](factory ServiceFactory) ServiceDescriptor {
+	return NewDescriptor[T](Pooled, factory)
+}
+
+// NewPooledFactoryForType creates a new pooled service descriptor for the given service type.
+func NewPooledFactoryForType(
+	serviceType reflect.Type,
+	factoryFunc SimpleServiceFactoryFunc) ServiceDescriptor {
+	return NewPooledServiceFactoryForType(serviceType, NewFactory(factoryFunc))
+}
+
+// NewPooledFactory creates a new pooled service descriptor for the given service type.
+func NewPooledFactory[T any](factoryFunc SimpleServiceFactoryFunc) ServiceDescriptor {
+	return NewPooledServiceFactory[T](NewFactory(factoryFunc))
+}
+
+// NewPooledStructForType creates a new pooled service descriptor for the given service type.
+func NewPooledStructForType(
+	serviceType reflect.Type, structType reflect.Type) (ServiceDescriptor, error) {
+	factory, err := NewStructFactoryForType(structType)
+	if err != nil {
+		return nil, err
+	}
+	return NewPooledServiceFactoryForType(serviceType, factory), nil
+}
+
+// NewPooledStruct creates a new pooled service descriptor for the given service type.
+func NewPooledStruct[T any, Impl any]() (ServiceDescriptor, error) {
+	factory, err := NewStructFactory[Impl]()
+	if err != nil {
+		return nil, err
+	}
+	return NewPooledServiceFactory[T](factory), nil
+}
+
+// NewPooledStructPtr creates a new pooled service descriptor for the given service type.
+func NewPooledStructPtr[Impl any]() (ServiceDescriptor, error) {
+	return NewPooledStruct[*Impl, Impl]()
+}
+
+// NewPerResolutionServiceFactoryForType creates a new per-resolution service descriptor for the given service type.
+func NewPerResolutionServiceFactoryForType(serviceType reflect.Type, factory ServiceFactory) ServiceDescriptor {
+	return NewDescriptorForType(serviceType, PerResolution, factory)
+}
+
+// NewPerResolutionServiceFactory creates a new per-resolution service descriptor for the given service type.
+func NewPerResolutionServiceFactory[T any](factory ServiceFactory) ServiceDescriptor {
+	return NewDescriptor[T](PerResolution, factory)
+}
+
+// NewPerResolutionFactoryForType creates a new per-resolution service descriptor for the given service type.
+func NewPerResolutionFactoryForType(
+	serviceType reflect.Type,
+	factoryFunc SimpleServiceFactoryFunc) ServiceDescriptor {
+	return NewPerResolutionServiceFactoryForType(serviceType, NewFactory(factoryFunc))
+}
+
+// NewPerResolutionFactory creates a new per-resolution service descriptor for the given service type.
+func NewPerResolutionFactory[T any](factoryFunc SimpleServiceFactoryFunc) ServiceDescriptor {
+	return NewPerResolutionServiceFactory[T](NewFactory(factoryFunc))
+}
+
+// NewPerResolutionStructForType creates a new per-resolution service descriptor for the given service type.
+func NewPerResolutionStructForType(
+	serviceType reflect.Type, structType reflect.Type) (ServiceDescriptor, error) {
+	factory, err := NewStructFactoryForType(structType)
+	if err != nil {
+		return nil, err
+	}
+	return NewPerResolutionServiceFactoryForType(serviceType, factory), nil
+}
+
+// NewPerResolutionStruct creates a new per-resolution service descriptor for the given service type.
+func NewPerResolutionStruct[T any, Impl any]() (ServiceDescriptor, error) {
+	factory, err := NewStructFactory[Impl]()
+	if err != nil {
+		return nil, err
+	}
+	return NewPerResolutionServiceFactory[T](factory), nil
+}
+
+// NewPerResolutionStructPtr creates a new per-resolution service descriptor for the given service type.
+func NewPerResolutionStructPtr[Impl any]() (ServiceDescriptor, error) {
+	return NewPerResolutionStruct[*Impl, Impl]()
+}
+
 // NewInstanceForType creates a new singleton service descriptor for the given service instance.
 func NewInstanceForType(serviceType reflect.Type, instance any) (ServiceDescriptor, error) {
 	factory, err := newInstanceFactory(instance)
@@ -328,6 +328,178 @@ func TestNewTransientStruct_OnNonStruct(t *testing.T) {
 	assert.Nil(t, descriptor)
 }
 
+func TestNewPooledServiceFactoryForType(t *testing.T) {
+	factory := &testServiceFactoryFunc{}
+	descriptor := NewPooledServiceFactoryForType(
+		typeOfTestServiceInterface,
+		factory)
+
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, Pooled, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.Equal(t, factory, descriptor.Factory())
+}
+
+func TestNewPooledServiceFactory(t *testing.T) {
+	factory := &testServiceFactoryFunc{}
+	descriptor := NewPooledServiceFactory[testServiceInterface](factory)
+
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, Pooled, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.Equal(t, factory, descriptor.Factory())
+}
+
+func TestNewPooledFactoryForType(t *testing.T) {
+	factory := func(provider ServiceProvider) (any, error) {
+		panic("not needed for test")
+	}
+	descriptor := NewPooledFactoryForType(
+		typeOfTestServiceInterface,
+		factory)
+
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, Pooled, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.NotNil(t, descriptor.Factory())
+}
+
+func TestNewPooledFactory(t *testing.T) {
+	factory := func(provider ServiceProvider) (any, error) {
+		panic("not needed for test")
+	}
+	descriptor := NewPooledFactory[testServiceInterface](factory)
+
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, Pooled, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.NotNil(t, descriptor.Factory())
+}
+
+func TestNewPooledStructForType(t *testing.T) {
+	descriptor, err := NewPooledStructForType(
+		typeOfTestServiceInterface,
+		typeOfTestServiceStruct)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, Pooled, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.NotNil(t, descriptor.Factory())
+}
+
+func TestNewPooledStructForType_OnNonStruct(t *testing.T) {
+	descriptor, err := NewPooledStructForType(
+		typeOfTestServiceInterface,
+		typeOfInt)
+
+	assert.Error(t, err)
+	assert.Nil(t, descriptor)
+}
+
+func TestNewPooledStruct(t *testing.T) {
+	descriptor, err := NewPooledStruct[testServiceInterface, testServiceStruct]()
+
+	assert.NoError(t, err)
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, Pooled, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.NotNil(t, descriptor.Factory())
+}
+
+func TestNewPooledStruct_OnNonStruct(t *testing.T) {
+	descriptor, err := NewPooledStruct[testServiceInterface, int64]()
+
+	assert.Error(t, err)
+	assert.Nil(t, descriptor)
+}
+
+func TestNewPerResolutionServiceFactoryForType(t *testing.T) {
+	factory := &testServiceFactoryFunc{}
+	descriptor := NewPerResolutionServiceFactoryForType(
+		typeOfTestServiceInterface,
+		factory)
+
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, PerResolution, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.Equal(t, factory, descriptor.Factory())
+}
+
+func TestNewPerResolutionServiceFactory(t *testing.T) {
+	factory := &testServiceFactoryFunc{}
+	descriptor := NewPerResolutionServiceFactory[testServiceInterface](factory)
+
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, PerResolution, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.Equal(t, factory, descriptor.Factory())
+}
+
+func TestNewPerResolutionFactoryForType(t *testing.T) {
+	factory := func(provider ServiceProvider) (any, error) {
+		panic("not needed for test")
+	}
+	descriptor := NewPerResolutionFactoryForType(
+		typeOfTestServiceInterface,
+		factory)
+
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, PerResolution, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.NotNil(t, descriptor.Factory())
+}
+
+func TestNewPerResolutionFactory(t *testing.T) {
+	factory := func(provider ServiceProvider) (any, error) {
+		panic("not needed for test")
+	}
+	descriptor := NewPerResolutionFactory[testServiceInterface](factory)
+
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, PerResolution, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.NotNil(t, descriptor.Factory())
+}
+
+func TestNewPerResolutionStructForType(t *testing.T) {
+	descriptor, err := NewPerResolutionStructForType(
+		typeOfTestServiceInterface,
+		typeOfTestServiceStruct)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, PerResolution, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.NotNil(t, descriptor.Factory())
+}
+
+func TestNewPerResolutionStructForType_OnNonStruct(t *testing.T) {
+	descriptor, err := NewPerResolutionStructForType(
+		typeOfTestServiceInterface,
+		typeOfInt)
+
+	assert.Error(t, err)
+	assert.Nil(t, descriptor)
+}
+
+func TestNewPerResolutionStruct(t *testing.T) {
+	descriptor, err := NewPerResolutionStruct[testServiceInterface, testServiceStruct]()
+
+	assert.NoError(t, err)
+	assert.NotNil(t, descriptor)
+	assert.Equal(t, PerResolution, descriptor.Lifetime())
+	assert.Equal(t, typeOfTestServiceInterface, descriptor.ServiceType())
+	assert.NotNil(t, descriptor.Factory())
+}
+
+func TestNewPerResolutionStruct_OnNonStruct(t *testing.T) {
+	descriptor, err := NewPerResolutionStruct[testServiceInterface, int64]()
+
+	assert.Error(t, err)
+	assert.Nil(t, descriptor)
+}
+
 func TestNewInstanceForType(t *testing.T) {
 	instance := &testServiceStruct{}
 	descriptor, err := NewInstanceForType(
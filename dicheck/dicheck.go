@@ -0,0 +1,367 @@
+// Package dicheck defines an analyzer reporting misuses of the di package
+// which are visible statically, and would otherwise fail or panic at run time.
+package dicheck
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const diPath = "github.com/go-mike/di"
+
+const doc = `report misuses of the dependency injection package
+
+The dicheck analyzer reports:
+  - New{Singleton,Scoped,Transient,Pooled,PerResolution}Struct[T, Impl] calls
+    where *Impl is not assignable to T, or Impl is not a struct type;
+  - struct activations, such as ActivateStruct, NewSingletonStructPtr or
+    AddScopedHandler, on struct types with unexported fields, which
+    reflection cannot set;
+  - function factories, such as NewFuncFactory, on functions not returning
+    a service and optionally an error;
+  - GetService[T] calls on service types never registered in the same
+    package, in packages registering services.`
+
+// Analyzer reports misuses of the di package.
+var Analyzer = &analysis.Analyzer{
+	Name:     "dicheck",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// checkUnregistered enables the report of services never registered.
+var checkUnregistered bool
+
+func init() {
+	Analyzer.Flags.BoolVar(&checkUnregistered, "unregistered", true,
+		"report GetService calls on service types never registered in the same package")
+}
+
+// Struct descriptor constructors, by the index of their struct type argument.
+var (
+	structImplementations = map[string]bool{
+		"NewSingletonStruct":     true,
+		"NewScopedStruct":        true,
+		"NewTransientStruct":     true,
+		"NewPooledStruct":        true,
+		"NewPerResolutionStruct": true,
+	}
+	structPointers = map[string]bool{
+		"NewSingletonStructPtr":     true,
+		"NewScopedStructPtr":        true,
+		"NewTransientStructPtr":     true,
+		"NewPooledStructPtr":        true,
+		"NewPerResolutionStructPtr": true,
+	}
+	structActivations = map[string]bool{
+		"ActivateStruct":        true,
+		"ActivateStructFactory": true,
+		"NewStructFactory":      true,
+	}
+	reflectedStructActivations = map[string]bool{
+		"ActivateStructFactoryForType":       true,
+		"ActivateStructSimpleFactoryForType": true,
+		"ActivateStructForType":              true,
+		"ActivateStructSimple":               true,
+		"NewStructFactoryForType":            true,
+	}
+	reflectedStructDescriptors = map[string]bool{
+		"NewSingletonStructForType":     true,
+		"NewScopedStructForType":        true,
+		"NewTransientStructForType":     true,
+		"NewPooledStructForType":        true,
+		"NewPerResolutionStructForType": true,
+	}
+	funcActivations = map[string]bool{
+		"NewFuncFactory":                   true,
+		"ActivateFuncFactoryForType":       true,
+		"ActivateFuncSimpleFactoryForType": true,
+		"ActivateFuncFactory":              true,
+		"ActivateFuncForType":              true,
+		"ActivateFuncSimple":               true,
+		"ActivateFunc":                     true,
+	}
+	serviceRegistrations = map[string]bool{
+		"NewDescriptor":                  true,
+		"NewSingletonServiceFactory":     true,
+		"NewSingletonFactory":            true,
+		"NewScopedServiceFactory":        true,
+		"NewScopedFactory":               true,
+		"NewTransientServiceFactory":     true,
+		"NewTransientFactory":            true,
+		"NewPooledServiceFactory":        true,
+		"NewPooledFactory":               true,
+		"NewPerResolutionServiceFactory": true,
+		"NewPerResolutionFactory":        true,
+		"NewInstance":                    true,
+		"NewScopeSupplied":               true,
+		"AddScopeSupplied":               true,
+		"NewParameterizedFactory":        true,
+		"AddParameterizedFactory":        true,
+		"ReplaceOf":                      true,
+	}
+	reflectedServiceRegistrations = map[string]bool{
+		"NewDescriptorForType":                  true,
+		"NewSingletonServiceFactoryForType":     true,
+		"NewSingletonFactoryForType":            true,
+		"NewScopedServiceFactoryForType":        true,
+		"NewScopedFactoryForType":               true,
+		"NewTransientServiceFactoryForType":     true,
+		"NewTransientFactoryForType":            true,
+		"NewPooledServiceFactoryForType":        true,
+		"NewPooledFactoryForType":               true,
+		"NewPerResolutionServiceFactoryForType": true,
+		"NewPerResolutionFactoryForType":        true,
+		"NewInstanceForType":                    true,
+		"NewScopeSuppliedForType":               true,
+		"NewParameterizedFactoryForType":        true,
+	}
+	optionsConfigurations = map[string]bool{
+		"Configure":      true,
+		"ConfigureNamed": true,
+	}
+)
+
+// resolution is a call to GetService.
+type resolution struct {
+	call        *ast.CallExpr
+	serviceType types.Type
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	// The di package resolves services registered by its callers
+	if pass.Pkg.Path() == diPath {
+		return nil, nil
+	}
+
+	var registered []types.Type
+	var resolutions []resolution
+	register := func(serviceType types.Type) {
+		if serviceType != nil {
+			registered = append(registered, serviceType)
+		}
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		fn, typeArgs := diCallee(pass.TypesInfo, call)
+		if fn == nil {
+			return
+		}
+		name := fn.Name()
+
+		switch {
+		case structImplementations[name] && typeArgs.Len() == 2:
+			register(typeArgs.At(0))
+			if checkStructType(pass, call, name, typeArgs.At(1)) {
+				checkImplementation(pass, call, name, typeArgs.At(0), typeArgs.At(1))
+			}
+		case structPointers[name] && typeArgs.Len() == 1:
+			register(types.NewPointer(typeArgs.At(0)))
+			checkStructType(pass, call, name, typeArgs.At(0))
+		case structActivations[name] && typeArgs.Len() == 1:
+			checkStructType(pass, call, name, typeArgs.At(0))
+		case reflectedStructActivations[name] && len(call.Args) > 0:
+			if structType := reflectedType(pass.TypesInfo, call.Args[0]); structType != nil {
+				checkStructType(pass, call, name, structType)
+			}
+		case reflectedStructDescriptors[name] && len(call.Args) > 1:
+			register(reflectedType(pass.TypesInfo, call.Args[0]))
+			if structType := reflectedType(pass.TypesInfo, call.Args[1]); structType != nil {
+				checkStructType(pass, call, name, structType)
+			}
+		case funcActivations[name] && len(call.Args) > 0:
+			checkFunc(pass, call, name, call.Args[0])
+		case serviceRegistrations[name] && typeArgs.Len() > 0:
+			register(typeArgs.At(0))
+		case reflectedServiceRegistrations[name] && len(call.Args) > 0:
+			register(reflectedType(pass.TypesInfo, call.Args[0]))
+		case name == "AddScopedHandler" && typeArgs.Len() == 2:
+			register(typeArgs.At(1))
+			checkStructType(pass, call, name, typeArgs.At(0))
+		case optionsConfigurations[name] && typeArgs.Len() == 1:
+			for _, generic := range []string{"Options", "OptionsMonitor", "OptionsSnapshot"} {
+				register(instantiate(fn.Pkg(), generic, typeArgs.At(0)))
+			}
+		case name == "GetService" && typeArgs.Len() == 1:
+			resolutions = append(resolutions, resolution{call: call, serviceType: typeArgs.At(0)})
+		}
+	})
+
+	// Packages registering nothing resolve services registered elsewhere
+	if !checkUnregistered || len(registered) == 0 {
+		return nil, nil
+	}
+	for _, resolution := range resolutions {
+		if !isResolvable(resolution.serviceType, registered) {
+			pass.Reportf(resolution.call.Pos(),
+				"GetService[%[1]s]: %[1]s is never registered in this package",
+				typeString(pass, resolution.serviceType))
+		}
+	}
+	return nil, nil
+}
+
+// diCallee returns the function of the di package called, with its type
+// arguments, or nil when another function is called.
+func diCallee(info *types.Info, call *ast.CallExpr) (*types.Func, *types.TypeList) {
+	fn := typeutil.StaticCallee(info, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != diPath {
+		return nil, nil
+	}
+	if ident := calleeIdent(call.Fun); ident != nil {
+		return fn, info.Instances[ident].TypeArgs
+	}
+	return fn, nil
+}
+
+func calleeIdent(fun ast.Expr) *ast.Ident {
+	switch fun := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return fun
+	case *ast.SelectorExpr:
+		return fun.Sel
+	case *ast.IndexExpr:
+		return calleeIdent(fun.X)
+	case *ast.IndexListExpr:
+		return calleeIdent(fun.X)
+	default:
+		return nil
+	}
+}
+
+// reflectedType returns T for the expressions reflect.TypeOf(value of T) and
+// reflect.TypeOf((*T)(nil)).Elem(), or nil for other expressions.
+func reflectedType(info *types.Info, expr ast.Expr) types.Type {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Elem" && len(call.Args) == 0 {
+		if pointer, ok := reflectedType(info, selector.X).(*types.Pointer); ok {
+			return pointer.Elem()
+		}
+		return nil
+	}
+	fn := typeutil.StaticCallee(info, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != "reflect" || fn.Name() != "TypeOf" || len(call.Args) != 1 {
+		return nil
+	}
+	return info.TypeOf(call.Args[0])
+}
+
+// checkStructType reports struct activations on types other than structs,
+// or on structs with unexported fields. It returns false on non-struct types.
+func checkStructType(pass *analysis.Pass, call *ast.CallExpr, name string, structType types.Type) bool {
+	if _, isParam := structType.(*types.TypeParam); isParam {
+		return false
+	}
+	fields, ok := structType.Underlying().(*types.Struct)
+	if !ok {
+		pass.Reportf(call.Pos(), "%s: %s is not a struct type", name, typeString(pass, structType))
+		return false
+	}
+	for i := 0; i < fields.NumFields(); i++ {
+		if field := fields.Field(i); !field.Exported() {
+			pass.Reportf(call.Pos(),
+				"%s: field %s of %s is unexported and cannot be set by reflection",
+				name, field.Name(), typeString(pass, structType))
+			return true
+		}
+	}
+	return true
+}
+
+// checkImplementation reports struct descriptors whose instances, pointers
+// to the struct, cannot be registered as the service type.
+func checkImplementation(pass *analysis.Pass, call *ast.CallExpr, name string, serviceType, structType types.Type) {
+	if _, isParam := serviceType.(*types.TypeParam); isParam {
+		return
+	}
+	instanceType := types.NewPointer(structType)
+	if types.AssignableTo(instanceType, serviceType) {
+		return
+	}
+	if iface, ok := serviceType.Underlying().(*types.Interface); ok {
+		method, _ := types.MissingMethod(instanceType, iface, true)
+		pass.Reportf(call.Pos(), "%s: %s does not implement %s (missing method %s)",
+			name, typeString(pass, instanceType), typeString(pass, serviceType), method.Name())
+		return
+	}
+	pass.Reportf(call.Pos(), "%s: %s is not assignable to %s",
+		name, typeString(pass, instanceType), typeString(pass, serviceType))
+}
+
+// checkFunc reports function factories on values which are not functions
+// returning a service and optionally an error.
+func checkFunc(pass *analysis.Pass, call *ast.CallExpr, name string, function ast.Expr) {
+	functionType := pass.TypesInfo.TypeOf(function)
+	if functionType == nil || types.IsInterface(functionType) {
+		return
+	}
+	signature, ok := functionType.Underlying().(*types.Signature)
+	if !ok {
+		pass.Reportf(function.Pos(), "%s: %s is not a function", name, typeString(pass, functionType))
+		return
+	}
+	results := signature.Results()
+	if results.Len() < 1 || results.Len() > 2 {
+		pass.Reportf(function.Pos(),
+			"%s: function returns %d results, want a service and optionally an error",
+			name, results.Len())
+		return
+	}
+	if results.Len() == 2 && !types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type()) {
+		pass.Reportf(function.Pos(),
+			"%s: second result of function is %s, want error",
+			name, typeString(pass, results.At(1).Type()))
+	}
+}
+
+// isResolvable tells whether a service type resolves from the registered types.
+func isResolvable(serviceType types.Type, registered []types.Type) bool {
+	switch serviceType.(type) {
+	case *types.TypeParam, *types.Slice:
+		// Slices of services never registered resolve empty
+		return true
+	}
+	if named, ok := serviceType.(*types.Named); ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == diPath {
+		switch named.Obj().Name() {
+		case "ServiceContainer":
+			return true
+		case "Meta", "Owned":
+			return isResolvable(named.TypeArgs().At(0), registered)
+		}
+	}
+	for _, registeredType := range registered {
+		if types.Identical(serviceType, registeredType) {
+			return true
+		}
+	}
+	return false
+}
+
+// instantiate returns the generic type of the di package with the given
+// name instantiated with the given type argument.
+func instantiate(pkg *types.Package, name string, typeArg types.Type) types.Type {
+	generic, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	instance, err := types.Instantiate(nil, generic.Type(), []types.Type{typeArg}, false)
+	if err != nil {
+		return nil
+	}
+	return instance
+}
+
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(pass.Pkg))
+}
@@ -0,0 +1,30 @@
+package dicheck
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// moduleDir is the root of the module, where the test packages are loaded from.
+func moduleDir(t *testing.T) string {
+	dir, err := filepath.Abs("..")
+	assert.NoError(t, err)
+	return dir
+}
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, moduleDir(t), Analyzer,
+		"github.com/go-mike/di/dicheck/testdata/registers",
+		"github.com/go-mike/di/dicheck/testdata/resolves")
+}
+
+func TestAnalyzer_Unregistered(t *testing.T) {
+	assert.NoError(t, Analyzer.Flags.Set("unregistered", "false"))
+	defer func() { assert.NoError(t, Analyzer.Flags.Set("unregistered", "true")) }()
+
+	// The package has no expectation, so no diagnostic must be reported
+	analysistest.Run(t, moduleDir(t), Analyzer, "github.com/go-mike/di/dicheck/testdata/unregistered")
+}
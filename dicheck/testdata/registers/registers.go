@@ -0,0 +1,144 @@
+package registers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/go-mike/di"
+)
+
+type Repo interface {
+	Find(id int) string
+}
+
+type SQLRepo struct {
+	DSN string
+}
+
+func (repo *SQLRepo) Find(id int) string {
+	return repo.DSN
+}
+
+type Other struct{}
+
+type hidden struct {
+	Repo  Repo
+	cache map[int]string
+}
+
+type Handler struct {
+	Repo Repo
+}
+
+func (handler *Handler) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+type User struct {
+	Name string
+}
+
+type Tenant string
+
+type Report struct {
+	Repo     Repo
+	Customer string
+}
+
+func NewReport(repo Repo, customer string) *Report {
+	return &Report{Repo: repo, Customer: customer}
+}
+
+type Settings struct {
+	Verbose bool
+}
+
+func NewRepo() (Repo, error) {
+	return &SQLRepo{}, nil
+}
+
+func NewTwo() (Repo, Repo) {
+	return nil, nil
+}
+
+func NewMany() (Repo, error, bool) {
+	return nil, nil, false
+}
+
+func NewNothing() {}
+
+func Register(services di.ServiceCollection) error {
+	repo, err := di.NewSingletonStruct[Repo, SQLRepo]()
+	if err != nil {
+		return err
+	}
+	services.Add(repo)
+
+	_, _ = di.NewScopedStruct[Repo, Other]()                                 // want `NewScopedStruct: \*Other does not implement Repo \(missing method Find\)`
+	_, _ = di.NewTransientStruct[io.Reader, int]()                           // want `NewTransientStruct: int is not a struct type`
+	_, _ = di.NewSingletonStruct[*Other, SQLRepo]()                          // want `NewSingletonStruct: \*SQLRepo is not assignable to \*Other`
+	_, _ = di.NewSingletonStructPtr[hidden]()                                // want `NewSingletonStructPtr: field cache of hidden is unexported and cannot be set by reflection`
+	_, _ = di.NewStructFactoryForType(reflect.TypeOf(hidden{}))              // want `NewStructFactoryForType: field cache of hidden is unexported`
+	_, _ = di.NewStructFactoryForType(reflect.TypeOf((*hidden)(nil)).Elem()) // want `NewStructFactoryForType: field cache of hidden is unexported`
+
+	_, _ = di.NewFuncFactory(NewRepo)
+	_, _ = di.NewFuncFactory(NewTwo)     // want `NewFuncFactory: second result of function is Repo, want error`
+	_, _ = di.NewFuncFactory(NewMany)    // want `NewFuncFactory: function returns 3 results, want a service and optionally an error`
+	_, _ = di.NewFuncFactory(NewNothing) // want `NewFuncFactory: function returns 0 results`
+	_, _ = di.NewFuncFactory(Settings{}) // want `NewFuncFactory: Settings is not a function`
+	var dynamic any = NewRepo
+	_, _ = di.NewFuncFactory(dynamic)
+
+	di.Configure[Settings](services)
+	di.AddScopeSupplied[*User](services)
+	services.Add(di.NewScopeSuppliedForType(reflect.TypeOf(Tenant(""))))
+	if err := di.AddParameterizedFactory[func(string) (*Report, error)](services, NewReport); err != nil {
+		return err
+	}
+	_, err = di.AddScopedHandler[Handler](services)
+	return err
+}
+
+var ErrNotFound = errors.New("not found")
+
+func Resolve(provider di.ServiceProvider) error {
+	if _, err := di.GetService[Repo](provider); err != nil {
+		return err
+	}
+	if _, err := di.GetService[di.Options[Settings]](provider); err != nil {
+		return err
+	}
+	if _, err := di.GetService[di.Meta[Repo]](provider); err != nil {
+		return err
+	}
+	if _, err := di.GetService[di.Owned[Repo]](provider); err != nil {
+		return err
+	}
+	if _, err := di.GetService[di.Owned[*SQLRepo]](provider); err != nil { // want `GetService\[.*di.Owned\[\*SQLRepo\]\]: .*di.Owned\[\*SQLRepo\] is never registered in this package`
+		return err
+	}
+	if _, err := di.GetService[*User](provider); err != nil {
+		return err
+	}
+	if _, err := di.GetService[Tenant](provider); err != nil {
+		return err
+	}
+	if _, err := di.GetService[func(string) (*Report, error)](provider); err != nil {
+		return err
+	}
+	if _, err := di.GetService[*Handler](provider); err != nil {
+		return err
+	}
+	if _, err := di.GetService[di.ServiceContainer](provider); err != nil {
+		return err
+	}
+	if _, err := di.GetService[[]*Other](provider); err != nil {
+		return err
+	}
+	_, err := di.GetService[*SQLRepo](provider) // want `GetService\[\*SQLRepo\]: \*SQLRepo is never registered in this package`
+	return err
+}
+
+func Generic[T any](provider di.ServiceProvider) (T, error) {
+	return di.GetService[T](provider)
+}
@@ -0,0 +1,11 @@
+// Package resolves registers no service, so its resolutions are not reported.
+package resolves
+
+import (
+	"github.com/go-mike/di"
+	"github.com/go-mike/di/dicheck/testdata/registers"
+)
+
+func Resolve(provider di.ServiceProvider) (*registers.SQLRepo, error) {
+	return di.GetService[*registers.SQLRepo](provider)
+}
@@ -0,0 +1,19 @@
+// Package unregistered resolves a service it never registers, which is only
+// reported when the unregistered check is enabled.
+package unregistered
+
+import "github.com/go-mike/di"
+
+type Service struct{}
+
+type Other struct{}
+
+func Register(services di.ServiceCollection) {
+	services.Add(di.NewSingletonFactory[*Service](func(di.ServiceProvider) (any, error) {
+		return &Service{}, nil
+	}))
+}
+
+func Resolve(provider di.ServiceProvider) (*Other, error) {
+	return di.GetService[*Other](provider)
+}
@@ -45,6 +45,14 @@ func findSlice[T any] (source []T, filter func (T) bool) *T {
 	return nil
 }
 
+func anySlice[T any] (source []T, predicate func (T) bool) bool {
+	return findSlice(source, predicate) != nil
+}
+
+func allSlice[T any] (source []T, predicate func (T) bool) bool {
+	return findSlice(source, func (item T) bool { return !predicate(item) }) == nil
+}
+
 func filterSliceIndices[T any] (source []T, filter func (T) bool) []int {
 	results := make([]int, 0)
 	for i, item := range source {
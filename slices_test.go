@@ -107,3 +107,17 @@ func TestRangeMapSlice(t *testing.T) {
 	expected := []int{2, 4, 6, 8, 10}
 	assert.Equal(t, expected, actual)
 }
+
+func TestAnySlice(t *testing.T) {
+	source := rangeSlice(1, 5)
+	assert.True(t, anySlice(source, func(item int) bool { return item == 3 }))
+	assert.False(t, anySlice(source, func(item int) bool { return item == 6 }))
+	assert.False(t, anySlice([]int{}, func(item int) bool { return true }))
+}
+
+func TestAllSlice(t *testing.T) {
+	source := rangeSlice(1, 5)
+	assert.True(t, allSlice(source, func(item int) bool { return item > 0 }))
+	assert.False(t, allSlice(source, func(item int) bool { return item < 5 }))
+	assert.True(t, allSlice([]int{}, func(item int) bool { return false }))
+}